@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// applyPatches applies the normalized patches as a single unit using git apply,
+// which either applies every file or none. The result is verified by checking
+// the patch now applies in reverse, and rolled back if that check fails.
+func applyPatches(patches []string) error {
+	patch, err := combinePatches(patches)
+	if err != nil {
+		return fmt.Errorf("invalid patch: %w", err)
+	}
+
+	if err := gitApply(patch, "--check"); err != nil {
+		return fmt.Errorf("patch does not apply: %w", err)
+	}
+
+	if err := gitApply(patch); err != nil {
+		return fmt.Errorf("failed to apply patch: %w", err)
+	}
+
+	if err := gitApply(patch, "--reverse", "--check"); err != nil {
+		if rbErr := gitApply(patch, "--reverse"); rbErr != nil {
+			return fmt.Errorf("verification failed (%v) and rollback failed: %w", err, rbErr)
+		}
+		return fmt.Errorf("verification failed, changes rolled back: %w", err)
+	}
+
+	return nil
+}
+
+// applyFilePatch applies the normalized patch of a single file using git apply,
+// checking it first so that a patch that doesn't apply leaves the tree untouched
+func applyFilePatch(patch string) error {
+	if err := validatePatch(patch); err != nil {
+		return fmt.Errorf("invalid patch: %w", err)
+	}
+
+	if err := gitApply(patch, "--check"); err != nil {
+		return fmt.Errorf("patch does not apply: %w", err)
+	}
+
+	if err := gitApply(patch); err != nil {
+		return fmt.Errorf("failed to apply patch: %w", err)
+	}
+
+	return nil
+}
+
+// gitApply runs git apply with the patch on stdin
+func gitApply(patch string, args ...string) error {
+	cmd := exec.Command("git", append([]string{"apply"}, args...)...)
+	cmd.Stdin = strings.NewReader(patch)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
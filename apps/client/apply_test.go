@@ -0,0 +1,248 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+// tempRepo creates a git repo with the files, and runs the test from within it
+// since git apply works on the current directory
+func tempRepo(t testing.TB, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	for path, content := range files {
+		writeTestFile(t, filepath.Join(dir, path), content)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+	return dir
+}
+
+func writeTestFile(t testing.TB, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readTestFile returns the content of the file, "<missing>" when it doesn't exist
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "<missing>"
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+// mustNormalize returns the normalized patch as emitted by the work step
+func mustNormalize(t *testing.T, path string, op ctxtypes.FileOperation, patch string) string {
+	t.Helper()
+	p, err := normalizePatch(path, path, op, patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+const mainGo = "package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n"
+
+func TestApplyFilePatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		op      ctxtypes.FileOperation
+		patch   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "update",
+			path:  "main.go",
+			op:    ctxtypes.FileOperationUpdate,
+			patch: "@@ -3,3 +3,3 @@\n func main() {\n-\tprintln(\"hello\")\n+\tprintln(\"world\")\n }\n",
+			want:  "package main\n\nfunc main() {\n\tprintln(\"world\")\n}\n",
+		},
+		{
+			name:  "create",
+			path:  "pkg/new.go",
+			op:    ctxtypes.FileOperationCreate,
+			patch: "@@ -0,0 +1,2 @@\n+package pkg\n+\n",
+			want:  "package pkg\n\n",
+		},
+		{
+			name:  "remove",
+			path:  "main.go",
+			op:    ctxtypes.FileOperationRemove,
+			patch: "@@ -1,5 +0,0 @@\n-package main\n-\n-func main() {\n-\tprintln(\"hello\")\n-}\n",
+			want:  "<missing>",
+		},
+		{
+			name:    "conflicting hunk",
+			path:    "main.go",
+			op:      ctxtypes.FileOperationUpdate,
+			patch:   "@@ -3,3 +3,3 @@\n func main() {\n-\tprintln(\"bonjour\")\n+\tprintln(\"world\")\n }\n",
+			want:    mainGo,
+			wantErr: "patch does not apply",
+		},
+		{
+			name:    "create over an existing file",
+			path:    "main.go",
+			op:      ctxtypes.FileOperationCreate,
+			patch:   "@@ -0,0 +1 @@\n+package other\n",
+			want:    mainGo,
+			wantErr: "patch does not apply",
+		},
+		{
+			name:    "miscounted hunk",
+			path:    "main.go",
+			op:      ctxtypes.FileOperationUpdate,
+			patch:   "@@ -3,4 +3,4 @@\n func main() {\n-\tprintln(\"hello\")\n+\tprintln(\"world\")\n }\n",
+			want:    mainGo,
+			wantErr: "invalid patch",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := tempRepo(t, map[string]string{"main.go": mainGo})
+
+			err := applyFilePatch(mustNormalize(t, tt.path, tt.op, tt.patch))
+			if tt.wantErr == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("got error %v, want %q", err, tt.wantErr)
+			}
+
+			if got := readTestFile(t, filepath.Join(dir, tt.path)); got != tt.want {
+				t.Errorf("%s is\n%s\nwant\n%s", tt.path, got, tt.want)
+			}
+			// the patch is applied in place, nothing is written next to the file
+			if entries, _ := filepath.Glob(filepath.Join(dir, "*.gitdiff")); len(entries) > 0 {
+				t.Errorf("unexpected files %q", entries)
+			}
+		})
+	}
+}
+
+func TestApplyPatches(t *testing.T) {
+	update := mustNormalize(t, "main.go", ctxtypes.FileOperationUpdate, "@@ -3,3 +3,3 @@\n func main() {\n-\tprintln(\"hello\")\n+\tprintln(\"world\")\n }\n")
+	create := mustNormalize(t, "pkg/new.go", ctxtypes.FileOperationCreate, "@@ -0,0 +1 @@\n+package pkg\n")
+	conflict := mustNormalize(t, "util.go", ctxtypes.FileOperationUpdate, "@@ -1 +1 @@\n-package other\n+package util\n")
+
+	t.Run("clean", func(t *testing.T) {
+		dir := tempRepo(t, map[string]string{"main.go": mainGo, "util.go": "package main\n"})
+
+		if err := applyPatches([]string{update, create}); err != nil {
+			t.Fatal(err)
+		}
+		if got := readTestFile(t, filepath.Join(dir, "main.go")); !strings.Contains(got, "world") {
+			t.Errorf("main.go wasn't updated:\n%s", got)
+		}
+		if got := readTestFile(t, filepath.Join(dir, "pkg/new.go")); got != "package pkg\n" {
+			t.Errorf("pkg/new.go is %q", got)
+		}
+	})
+
+	// a conflicting file leaves every file of the patch untouched
+	t.Run("conflicting hunk", func(t *testing.T) {
+		dir := tempRepo(t, map[string]string{"main.go": mainGo, "util.go": "package main\n"})
+
+		err := applyPatches([]string{update, create, conflict})
+		if err == nil || !strings.Contains(err.Error(), "patch does not apply") {
+			t.Fatalf("got error %v, want the patch to be refused", err)
+		}
+		if got := readTestFile(t, filepath.Join(dir, "main.go")); got != mainGo {
+			t.Errorf("main.go was changed:\n%s", got)
+		}
+		if got := readTestFile(t, filepath.Join(dir, "pkg/new.go")); got != "<missing>" {
+			t.Errorf("pkg/new.go was created: %q", got)
+		}
+	})
+
+	// a clean filter that isn't idempotent makes the applied change differ
+	// from the patch, which must be reported rather than trusted
+	t.Run("verification failure", func(t *testing.T) {
+		dir := tempRepo(t, map[string]string{"main.go": "a\n", ".gitattributes": "main.go filter=prefix\n"})
+		for _, kv := range [][]string{{"filter.prefix.clean", "sed s/^/x/"}, {"filter.prefix.smudge", "cat"}} {
+			if out, err := exec.Command("git", "config", kv[0], kv[1]).CombinedOutput(); err != nil {
+				t.Fatalf("git config: %v: %s", err, out)
+			}
+		}
+
+		err := applyPatches([]string{mustNormalize(t, "main.go", ctxtypes.FileOperationUpdate, "@@ -1 +1 @@\n-xa\n+xb\n")})
+		if err == nil || !strings.Contains(err.Error(), "verification failed") {
+			t.Fatalf("got error %v, want the verification to fail", err)
+		}
+		if got := readTestFile(t, filepath.Join(dir, "main.go")); got != "xb\n" {
+			t.Errorf("main.go is %q", got)
+		}
+	})
+}
+
+func TestApplyMovePatch(t *testing.T) {
+	hunk := "@@ -3,3 +3,3 @@\n func main() {\n-\tprintln(\"hello\")\n+\tprintln(\"world\")\n }\n"
+	want := "package main\n\nfunc main() {\n\tprintln(\"world\")\n}\n"
+
+	// not moved yet, the patch moves the file
+	t.Run("rename", func(t *testing.T) {
+		dir := tempRepo(t, map[string]string{"main.go": mainGo})
+
+		p, err := normalizePatch("cmd/app/main.go", "main.go", ctxtypes.FileOperationMove, hunk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(p, "diff --git a/main.go b/cmd/app/main.go\nrename from main.go\nrename to cmd/app/main.go\n--- a/main.go\n+++ b/cmd/app/main.go\n") {
+			t.Fatalf("got headers\n%s", p)
+		}
+		if err := applyPatches([]string{p}); err != nil {
+			t.Fatal(err)
+		}
+		if got := readTestFile(t, filepath.Join(dir, "main.go")); got != "<missing>" {
+			t.Errorf("main.go is still there: %q", got)
+		}
+		if got := readTestFile(t, filepath.Join(dir, "cmd/app/main.go")); got != want {
+			t.Errorf("cmd/app/main.go is %q", got)
+		}
+	})
+
+	// already moved, the patch only edits the file at its new path
+	t.Run("moved", func(t *testing.T) {
+		dir := tempRepo(t, map[string]string{"cmd/app/main.go": mainGo})
+
+		p, err := normalizePatch("cmd/app/main.go", "cmd/app/main.go", ctxtypes.FileOperationMove, hunk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(p, "rename") {
+			t.Fatalf("got a rename in\n%s", p)
+		}
+		if err := applyFilePatch(p); err != nil {
+			t.Fatal(err)
+		}
+		if got := readTestFile(t, filepath.Join(dir, "cmd/app/main.go")); got != want {
+			t.Errorf("cmd/app/main.go is %q", got)
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	ctxutils "github.com/cyber-nic/ctx/libs/utils"
+	"github.com/rs/zerolog/log"
+)
+
+const bundleManifestFile = "manifest.json"
+
+var unsafeBundleCharsRegex = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// bundleEntry describes a single patch of a bundle
+type bundleEntry struct {
+	Path      string                 `json:"path"`
+	Operation ctxtypes.FileOperation `json:"operation"`
+	File      string                 `json:"file"`
+	// From is the path of a moved file before the move, which the patch renames
+	From string `json:"from,omitempty"`
+	// BaseHash is the content hash of the file the patch was generated against, empty for new files
+	BaseHash string `json:"base_hash,omitempty"`
+	// Confidence and Assumptions are the model's assessment of the patch, if any
+	Confidence  *float64 `json:"confidence,omitempty"`
+	Assumptions []string `json:"assumptions,omitempty"`
+}
+
+// bundleManifest lists the patches of a bundle in the order they are applied
+type bundleManifest struct {
+	Created string        `json:"created"`
+	Prompt  string        `json:"prompt,omitempty"`
+	Patches []bundleEntry `json:"patches"`
+}
+
+// patchBundle collects normalized patches to be written to a directory
+type patchBundle struct {
+	manifest bundleManifest
+	patches  []string
+}
+
+// add records the normalized patch of path, from is the source of a move and
+// original the content the patch was generated against
+func (b *patchBundle) add(path, from string, op ctxtypes.FileOperation, patch string, original string, data ctxtypes.PatchData) {
+	entry := bundleEntry{
+		Path:        path,
+		Operation:   op,
+		File:        fmt.Sprintf("%04d-%s.patch", len(b.patches)+1, unsafeBundleCharsRegex.ReplaceAllString(path, "_")),
+		Confidence:  data.Confidence,
+		Assumptions: data.Assumptions,
+	}
+	if op == ctxtypes.FileOperationMove && from != path {
+		entry.From = from
+	}
+	if op != ctxtypes.FileOperationCreate {
+		entry.BaseHash = ctxtypes.ContentHash(original)
+	}
+
+	b.manifest.Patches = append(b.manifest.Patches, entry)
+	b.patches = append(b.patches, patch)
+}
+
+// write saves the patches and manifest to dir
+func (b *patchBundle) write(dir string, prompt string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for i, entry := range b.manifest.Patches {
+		if err := os.WriteFile(filepath.Join(dir, entry.File), []byte(b.patches[i]), 0644); err != nil {
+			return err
+		}
+	}
+
+	b.manifest.Created = time.Now().Format(time.RFC3339)
+	b.manifest.Prompt = prompt
+
+	data, err := json.MarshalIndent(b.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, bundleManifestFile), data, 0644)
+}
+
+// runApplyBundle implements the apply-bundle command, which applies a bundle
+// written with -out-dir without connecting to the server.
+func runApplyBundle(args []string) {
+	fs := flag.NewFlagSet("apply-bundle", flag.ExitOnError)
+	var debug = fs.Bool("debug", false, "enable debug mode")
+	var force = fs.Bool("force", false, "apply even if files changed since the patches were generated")
+	var rootFlag = fs.String("root", "", "repo root the patches apply to, detected from .git or go.mod when empty")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s apply-bundle [flags] <dir>\n", filepath.Base(os.Args[0]))
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	ctxutils.ConfigLogging(debug)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	// patch paths are relative to the repo root
+	_, invokedFrom, err := enterRepoRoot(*rootFlag)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error locating repo root")
+	}
+	dir := absFrom(invokedFrom, fs.Arg(0))
+
+	manifest, err := applyBundle(dir, *force)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error applying bundle")
+	}
+
+	for _, entry := range manifest.Patches {
+		fmt.Printf("applied | %s\n", entry.Path)
+	}
+}
+
+// applyBundle applies the bundle in dir to the current directory, refusing
+// files changed since the patches were generated unless force is set. It
+// returns the manifest of the bundle.
+func applyBundle(dir string, force bool) (bundleManifest, error) {
+	var manifest bundleManifest
+
+	data, err := os.ReadFile(filepath.Join(dir, bundleManifestFile))
+	if err != nil {
+		return manifest, fmt.Errorf("failed to read bundle manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("failed to parse bundle manifest: %w", err)
+	}
+
+	patches := []string{}
+	for _, entry := range manifest.Patches {
+		// the patch only applies cleanly against the content it was generated
+		// from, a moved file is still at its old path
+		if entry.BaseHash != "" && !force {
+			path := entry.Path
+			if entry.From != "" {
+				path = entry.From
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return manifest, fmt.Errorf("failed to read patched file %s: %w", path, err)
+			}
+			if ctxtypes.ContentHash(string(content)) != entry.BaseHash {
+				return manifest, fmt.Errorf("%s changed since the patch was generated, use -force to apply anyway", path)
+			}
+		}
+
+		patch, err := os.ReadFile(filepath.Join(dir, entry.File))
+		if err != nil {
+			return manifest, fmt.Errorf("failed to read patch %s: %w", entry.File, err)
+		}
+		patches = append(patches, string(patch))
+	}
+
+	if err := applyPatches(patches); err != nil {
+		return manifest, err
+	}
+	return manifest, nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+// writeBundle writes a bundle of the changes to the files of mainGo and
+// util.go, as generated with -out-dir, and returns its directory
+func writeBundle(t *testing.T) string {
+	t.Helper()
+	changes := []struct {
+		path, from string
+		op         ctxtypes.FileOperation
+		patch      string
+		original   string
+	}{
+		{"util.go", "util.go", ctxtypes.FileOperationUpdate, "@@ -1 +1 @@\n-package main\n+package util\n", "package main\n"},
+		{"cmd/app/main.go", "main.go", ctxtypes.FileOperationMove, "@@ -3,3 +3,3 @@\n func main() {\n-\tprintln(\"hello\")\n+\tprintln(\"world\")\n }\n", mainGo},
+		{"pkg/new.go", "pkg/new.go", ctxtypes.FileOperationCreate, "@@ -0,0 +1 @@\n+package pkg\n", ""},
+	}
+
+	b := patchBundle{}
+	for _, c := range changes {
+		p, err := normalizePatch(c.path, c.from, c.op, c.patch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.add(c.path, c.from, c.op, p, c.original, ctxtypes.PatchData{})
+	}
+	dir := filepath.Join(t.TempDir(), "bundle")
+	if err := b.write(dir, "move main"); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestApplyBundle(t *testing.T) {
+	files := map[string]string{"main.go": mainGo, "util.go": "package main\n"}
+
+	// generated on one machine, applied on another
+	tempRepo(t, files)
+	bundle := writeBundle(t)
+	dir := tempRepo(t, files)
+
+	manifest, err := applyBundle(bundle, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved := manifest.Patches[1]; moved.From != "main.go" || moved.Path != "cmd/app/main.go" {
+		t.Errorf("the move is recorded as %+v", moved)
+	}
+	if e := manifest.Patches[0]; e.From != "" {
+		t.Errorf("the update is recorded as %+v", e)
+	}
+
+	want := map[string]string{
+		"main.go":         "<missing>",
+		"cmd/app/main.go": strings.Replace(mainGo, "hello", "world", 1),
+		"util.go":         "package util\n",
+		"pkg/new.go":      "package pkg\n",
+	}
+	for path, content := range want {
+		if got := readTestFile(t, filepath.Join(dir, path)); got != content {
+			t.Errorf("%s is %q, want %q", path, got, content)
+		}
+	}
+}
+
+func TestApplyBundleChangedFile(t *testing.T) {
+	files := map[string]string{"main.go": mainGo, "util.go": "package main\n"}
+	tempRepo(t, files)
+	bundle := writeBundle(t)
+
+	// the moved file changed at its old path
+	files["main.go"] = strings.Replace(mainGo, "hello", "bonjour", 1)
+	dir := tempRepo(t, files)
+
+	if _, err := applyBundle(bundle, false); err == nil || !strings.Contains(err.Error(), "main.go changed") {
+		t.Fatalf("got %v, want main.go refused", err)
+	}
+	if got := readTestFile(t, filepath.Join(dir, "util.go")); got != "package main\n" {
+		t.Errorf("util.go was changed to %q", got)
+	}
+}
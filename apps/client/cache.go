@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cyber-nic/ctx/apps/client/mapper"
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// keywordCacheFile holds the keywords of the files parsed by previous runs, relative to the repo root
+	keywordCacheFile = ".ctx/cache/keywords.json"
+	// keywordCacheVersion is bumped when extraction changes, discarding the keywords cached before
+	keywordCacheVersion = 1
+)
+
+// keywordCache maps the content of a file, along with the options its keywords
+// depend on, to its keywords. It is safe for concurrent use.
+type keywordCache struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string][]string
+	// used are the entries read or added by this run, the only ones saved
+	used         map[string][]string
+	hits, misses int
+}
+
+// keywordCacheData is the content of the cache file
+type keywordCacheData struct {
+	Version int                 `json:"version"`
+	Entries map[string][]string `json:"entries"`
+}
+
+// loadKeywordCache reads the cache file. A missing, unreadable or outdated file yields an empty cache.
+func loadKeywordCache(path string) *keywordCache {
+	c := &keywordCache{path: path, entries: map[string][]string{}, used: map[string][]string{}}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Warn().Err(err).Str("file", path).Msg("Failed to read keyword cache")
+		}
+		return c
+	}
+
+	var cached keywordCacheData
+	if err := json.Unmarshal(data, &cached); err != nil {
+		log.Warn().Err(err).Str("file", path).Msg("Discarding invalid keyword cache")
+		return c
+	}
+	if cached.Version == keywordCacheVersion && cached.Entries != nil {
+		c.entries = cached.Entries
+	}
+
+	return c
+}
+
+// keywordCacheKey identifies the keywords of the file content: its language,
+// the options extraction depends on and the hash of the content
+func keywordCacheKey(filePath string, code []byte, opts parseOptions) string {
+	chunkSize := 0
+	if opts.chunkSize > 0 && len(code) > opts.chunkSize {
+		chunkSize = opts.chunkSize
+	}
+	return fmt.Sprintf("%s:%t:%d:%s", mapper.LanguageName(filePath), opts.comments, chunkSize, ctxtypes.ContentHash(string(code)))
+}
+
+// get returns the cached keywords of the key
+func (c *keywordCache) get(key string) ([]string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keywords, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.used[key] = keywords
+	return keywords, true
+}
+
+// put caches the keywords of the key
+func (c *keywordCache) put(key string, keywords []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = keywords
+	c.used[key] = keywords
+}
+
+// save writes the entries used by this run, dropping those of files that changed or are gone
+func (c *keywordCache) save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	log.Debug().Int("hits", c.hits).Int("misses", c.misses).Msg("Keyword cache")
+
+	data, err := json.Marshal(keywordCacheData{Version: keywordCacheVersion, Entries: c.used})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0644)
+}
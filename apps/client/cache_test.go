@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestKeywordCacheSecondRun(t *testing.T) {
+	tempRepo(t, map[string]string{
+		"a.go":     "package a\n\nfunc Alpha() {}\n",
+		"lib/b.py": "def beta():\n    pass\n",
+	})
+
+	first := &parseCounter{}
+	tree, _ := walkTree(t, parseOptions{parsed: first.parsed})
+	if got, want := first.sorted(), []string{"a.go", "lib/b.py"}; !slices.Equal(got, want) {
+		t.Fatalf("first run parsed %q, want %q", got, want)
+	}
+	if _, err := os.Stat(keywordCacheFile); err != nil {
+		t.Fatalf("the cache wasn't saved: %v", err)
+	}
+
+	// nothing changed, every file is read from the cache
+	second := &parseCounter{}
+	cached, _ := walkTree(t, parseOptions{parsed: second.parsed})
+	if got := second.sorted(); len(got) != 0 {
+		t.Errorf("second run parsed %q", got)
+	}
+	// the cache directory itself is ignored
+	if node := cached.Children[".ctx"]; node == nil || !node.Skip {
+		t.Errorf(".ctx is %+v, want it skipped", node)
+	}
+	delete(cached.Children, ".ctx")
+	if !reflect.DeepEqual(cached, tree) {
+		t.Errorf("the cached tree differs from the parsed one")
+	}
+
+	// a changed file misses the cache
+	writeTestFile(t, "a.go", "package a\n\nfunc Gamma() {}\n")
+	third := &parseCounter{}
+	changed, _ := walkTree(t, parseOptions{parsed: third.parsed})
+	if got, want := third.sorted(), []string{"a.go"}; !slices.Equal(got, want) {
+		t.Errorf("after a change parsed %q, want %q", got, want)
+	}
+	if kws := changed.Children["a.go"].Keywords; !slices.Contains(kws, "Gamma") || slices.Contains(kws, "Alpha") {
+		t.Errorf("a.go has stale keywords %q", kws)
+	}
+
+	// keywords depend on the options
+	withComments := &parseCounter{}
+	walkTree(t, parseOptions{parsed: withComments.parsed, comments: true})
+	if got := withComments.sorted(); len(got) != 2 {
+		t.Errorf("with comments parsed %q, want every file", got)
+	}
+
+	// -no-cache parses every file
+	uncached := &parseCounter{}
+	walkTree(t, parseOptions{parsed: uncached.parsed, noCache: true})
+	if got := uncached.sorted(); len(got) != 2 {
+		t.Errorf("without the cache parsed %q, want every file", got)
+	}
+}
+
+func TestLoadKeywordCacheDiscarded(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid", "{"},
+		{"outdated", `{"version":0,"entries":{"go:false:0:h":["a"]}}`},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "keywords.json")
+		writeTestFile(t, path, tt.content)
+		if c := loadKeywordCache(path); len(c.entries) != 0 {
+			t.Errorf("%s: got entries %v", tt.name, c.entries)
+		}
+	}
+
+	if c := loadKeywordCache(filepath.Join(t.TempDir(), "missing.json")); c == nil || len(c.entries) != 0 {
+		t.Error("a missing cache isn't empty")
+	}
+}
+
+func TestKeywordCacheSavesUsedEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keywords.json")
+	c := loadKeywordCache(path)
+	c.put("kept", []string{"a"})
+	if err := c.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// entries not used by a run are dropped, e.g. those of deleted files
+	c = loadKeywordCache(path)
+	c.put("new", []string{"b"})
+	if err := c.save(); err != nil {
+		t.Fatal(err)
+	}
+	c = loadKeywordCache(path)
+	if _, ok := c.get("kept"); ok {
+		t.Error("an unused entry was kept")
+	}
+	if kws, ok := c.get("new"); !ok || !slices.Equal(kws, []string{"b"}) {
+		t.Errorf("got %q, %t for the new entry", kws, ok)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cyber-nic/ctx/apps/client/mapper"
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// chunkOverlap is how much consecutive chunks overlap so that declarations
+// cut at a chunk boundary appear whole in at least one chunk
+const chunkOverlap = 4 << 10
+
+// chunkSource splits code into windows of about size bytes. Windows end on a
+// line boundary and start up to overlap bytes before the end of the previous one.
+func chunkSource(code []byte, size, overlap int) [][]byte {
+	chunks := [][]byte{}
+
+	for start := 0; start < len(code); {
+		end := start + size
+		if end >= len(code) {
+			chunks = append(chunks, code[start:])
+			break
+		}
+
+		if i := bytes.IndexByte(code[end:], '\n'); i >= 0 {
+			end += i + 1
+		} else {
+			end = len(code)
+		}
+		chunks = append(chunks, code[start:end])
+
+		next := end - overlap
+		if i := bytes.LastIndexByte(code[:next], '\n'); i >= 0 {
+			next = i + 1
+		}
+		if next <= start {
+			next = end
+		}
+		start = next
+	}
+
+	return chunks
+}
+
+// parseChunks extracts the keywords of each chunk and merges them, without duplicates
+func parseChunks(parser *sitter.Parser, filePath string, chunks [][]byte, comments bool) ([]string, error) {
+	seen := map[string]bool{}
+	keywords := []string{}
+
+	for i, chunk := range chunks {
+		tree := parser.Parse(chunk, nil)
+		if tree == nil {
+			return nil, fmt.Errorf("parser returned no tree for chunk %d", i)
+		}
+
+		codeMap, err := mapper.GetCodeMap(tree.RootNode(), filePath, chunk, comments)
+		tree.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build code map: %w", err)
+		}
+
+		for _, k := range codeMap {
+			if !seen[k] {
+				seen[k] = true
+				keywords = append(keywords, k)
+			}
+		}
+	}
+
+	return keywords, nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// largeSource returns a Go file of n functions, about 150 bytes each
+func largeSource(n int) []byte {
+	var b bytes.Buffer
+	b.WriteString("package large\n\n")
+	for i := range n {
+		fmt.Fprintf(&b, "// handle%d serves the request %d\nfunc handle%d(req%d *Request) error {\n\treturn process(req%d, %d)\n}\n\n", i, i, i, i, i, i)
+	}
+	return b.Bytes()
+}
+
+// writeLargeSource writes a Go file of n functions to a temp dir and returns its path
+func writeLargeSource(tb testing.TB, n int) string {
+	tb.Helper()
+	path := filepath.Join(tb.TempDir(), "large.go")
+	if err := os.WriteFile(path, largeSource(n), 0644); err != nil {
+		tb.Fatal(err)
+	}
+	return path
+}
+
+func TestChunkSource(t *testing.T) {
+	code := largeSource(200)
+	size, overlap := 1<<10, 256
+	chunks := chunkSource(code, size, overlap)
+	if len(chunks) < 2 {
+		t.Fatalf("got %d chunks", len(chunks))
+	}
+
+	// the chunks are windows of code, from its start to its end, each starting on a line
+	prevStart, prevEnd := -1, 0
+	for i, chunk := range chunks {
+		start := bytes.Index(code, chunk)
+		if start < 0 {
+			t.Fatalf("chunk %d isn't a window of the code", i)
+		}
+		end := start + len(chunk)
+		if i == 0 && start != 0 {
+			t.Errorf("the first chunk starts at %d", start)
+		}
+		// a chunk starts on the line overlap bytes before the end of the previous one
+		if i > 0 && (start <= prevStart || start > prevEnd || bytes.Contains(code[start:max(start, prevEnd-overlap)], []byte("\n"))) {
+			t.Errorf("chunk %d spans [%d, %d) after [%d, %d)", i, start, end, prevStart, prevEnd)
+		}
+		if start > 0 && code[start-1] != '\n' {
+			t.Errorf("chunk %d starts mid-line", i)
+		}
+		if i < len(chunks)-1 && !bytes.HasSuffix(chunk, []byte("\n")) {
+			t.Errorf("chunk %d ends mid-line", i)
+		}
+		if len(chunk) > size+overlap {
+			t.Errorf("chunk %d is %d bytes", i, len(chunk))
+		}
+		prevStart, prevEnd = start, end
+	}
+	if prevEnd != len(code) {
+		t.Errorf("the last chunk ends at %d of %d", prevEnd, len(code))
+	}
+}
+
+func TestChunkSourceSmall(t *testing.T) {
+	code := []byte("package a\n")
+	if chunks := chunkSource(code, 1<<10, 256); len(chunks) != 1 || !bytes.Equal(chunks[0], code) {
+		t.Errorf("got %q", chunks)
+	}
+	if chunks := chunkSource(nil, 1<<10, 256); len(chunks) != 0 {
+		t.Errorf("got %q for no code", chunks)
+	}
+}
+
+func TestParseFileChunked(t *testing.T) {
+	n := 500
+	path := writeLargeSource(t, n)
+
+	full, err := parseFile(path, parseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunked, err := parseFile(path, parseOptions{chunkSize: 4 << 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// merged keywords appear once, the declarations cut at chunk boundaries included
+	seen := map[string]bool{}
+	for _, k := range chunked {
+		if seen[k] {
+			t.Errorf("%q is duplicated", k)
+		}
+		seen[k] = true
+	}
+	for _, k := range full {
+		if !seen[k] {
+			t.Errorf("%q is missing from the chunked keywords", k)
+		}
+	}
+	for i := range n {
+		if name := fmt.Sprintf("handle%d", i); !slices.Contains(chunked, name) {
+			t.Errorf("%s is missing", name)
+		}
+	}
+}
+
+func BenchmarkParseFile(b *testing.B) {
+	// about 3MB
+	path := writeLargeSource(b, 20000)
+
+	for _, bb := range []struct {
+		name      string
+		chunkSize int
+	}{
+		{"full", 0},
+		{"chunked", 256 << 10},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				if _, err := parseFile(path, parseOptions{chunkSize: bb.chunkSize}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cyber-nic/ctx/apps/client/mapper"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultAddr    = "localhost:8000"
+	configDirName  = "ctx"
+	configFileName = "config.json"
+	wsPath         = "/data"
+)
+
+// clientConfig holds user settings read from the ctx config file
+type clientConfig struct {
+	Addr string `json:"addr,omitempty"`
+	// Languages sets parse limits per language name, e.g. "go" or "javascript"
+	Languages map[string]languageLimits `json:"languages,omitempty"`
+	// Personas replace the server's work guidance per project type, e.g. "go", an empty one disables it
+	Personas map[string]string `json:"personas,omitempty"`
+}
+
+// languageLimits overrides the default parse limits of a language, 0 disables a limit
+type languageLimits struct {
+	MaxFileSize *int64 `json:"max_file_size,omitempty"`
+	MaxLines    *int   `json:"max_lines,omitempty"`
+}
+
+// configDir returns the ctx directory under the user's config directory, e.g. ~/.config/ctx
+func configDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, configDirName), nil
+}
+
+// loadConfig reads the config file if present. A missing file yields the zero config.
+func loadConfig() clientConfig {
+	cfg := clientConfig{}
+
+	dir, err := configDir()
+	if err != nil {
+		log.Debug().Err(err).Msg("no user config directory")
+		return cfg
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, configFileName))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Warn().Err(err).Msg("Failed to read config file")
+		}
+		return cfg
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		log.Warn().Err(err).Msg("Failed to parse config file")
+	}
+
+	return cfg
+}
+
+// isFlagSet reports whether the named flag was provided on the command line
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// resolveAddr picks the server address by precedence: flag, CTX_ADDR, config file, default
+func resolveAddr(flagAddr string, cfg clientConfig) string {
+	if isFlagSet("addr") && flagAddr != "" {
+		return flagAddr
+	}
+	if addr := os.Getenv("CTX_ADDR"); addr != "" {
+		return addr
+	}
+	if cfg.Addr != "" {
+		return cfg.Addr
+	}
+	return defaultAddr
+}
+
+// resolveLimits returns the parse limits of each language: the extractor defaults, replaced
+// by the config file, then by the global limits given on the command line
+func resolveLimits(cfg clientConfig, global parseOptions, sizeSet, linesSet bool) map[string]mapper.Limits {
+	limits := mapper.DefaultLimits()
+
+	for name, override := range cfg.Languages {
+		l, ok := limits[name]
+		if !ok {
+			log.Warn().Str("language", name).Msg("Unknown language in config limits")
+			continue
+		}
+		if override.MaxFileSize != nil {
+			l.MaxFileSize = *override.MaxFileSize
+		}
+		if override.MaxLines != nil {
+			l.MaxLines = *override.MaxLines
+		}
+		limits[name] = l
+	}
+
+	for name, l := range limits {
+		if sizeSet {
+			l.MaxFileSize = global.maxFileSize
+		}
+		if linesSet {
+			l.MaxLines = global.maxLines
+		}
+		limits[name] = l
+	}
+
+	return limits
+}
+
+// serverURL converts an address into a websocket URL. Bare host:port addresses
+// use ws. ctx://host selects wss, except for loopback hosts which use ws.
+func serverURL(addr string) (url.URL, error) {
+	if !strings.Contains(addr, "://") {
+		return url.URL{Scheme: "ws", Host: addr, Path: wsPath}, nil
+	}
+
+	u, err := url.Parse(addr)
+	if err != nil {
+		return url.URL{}, fmt.Errorf("invalid server address %q: %w", addr, err)
+	}
+
+	switch u.Scheme {
+	case "ws", "wss":
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	case "ctx":
+		u.Scheme = "wss"
+		if isLoopback(u.Hostname()) {
+			u.Scheme = "ws"
+		}
+	default:
+		return url.URL{}, fmt.Errorf("unsupported scheme %q in server address", u.Scheme)
+	}
+
+	if u.Path == "" || u.Path == "/" {
+		u.Path = wsPath
+	}
+
+	return *u, nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,15 @@
+// Package tree_sitter_dockerfile is the tree-sitter grammar of Dockerfiles.
+// src holds the parser generated from github.com/camdencheek/tree-sitter-dockerfile
+// v0.1.2 (MIT), which has no Go bindings.
+package tree_sitter_dockerfile
+
+// #cgo CFLAGS: -std=c11 -fPIC
+// #include "src/parser.c"
+import "C"
+
+import "unsafe"
+
+// Language returns the tree-sitter Language of Dockerfiles
+func Language() unsafe.Pointer {
+	return unsafe.Pointer(C.tree_sitter_dockerfile())
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+// extractPathHints returns the files of the context tree mentioned in the prompt.
+// A token matches a file when it equals its path or the trailing components of it,
+// e.g. "handler.go" or "api/handler.go" both match "internal/api/handler.go".
+func extractPathHints(prompt string, appCtx ctxtypes.ApplicationContext) []string {
+	tokens := map[string]bool{}
+	for _, t := range strings.Fields(prompt) {
+		t = strings.Trim(t, "`'\"()[]{}<>,;:!?")
+		t = strings.TrimSuffix(strings.TrimPrefix(t, "./"), ".")
+		if t != "" {
+			tokens[t] = true
+		}
+	}
+
+	hints := []string{}
+	if len(tokens) == 0 {
+		return hints
+	}
+
+	ctxtypes.WalkFiles(appCtx, func(p string, _ *ctxtypes.FileSystemNode) {
+		if tokens[p] {
+			hints = append(hints, p)
+			return
+		}
+		for t := range tokens {
+			if strings.HasSuffix(p, "/"+t) && path.Base(t) == path.Base(p) {
+				hints = append(hints, p)
+				return
+			}
+		}
+	})
+
+	sort.Strings(hints)
+
+	return hints
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/term"
+)
+
+const (
+	historyFile = ".ctxhistory"
+	historyMax  = 500
+)
+
+// promptHistory holds the past prompts of a repo, oldest first, and appends
+// new ones to the history file. The file is rewritten with the most recent
+// historyMax prompts once it holds more. It implements term.History.
+type promptHistory struct {
+	path    string
+	entries []string
+	// saved is the number of prompts in the history file
+	saved int
+}
+
+// loadHistory reads the history file. A missing or unreadable file yields an empty history.
+func loadHistory(path string) *promptHistory {
+	h := &promptHistory{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Warn().Err(err).Str("file", path).Msg("Failed to read prompt history")
+		}
+		return h
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	h.saved = len(h.entries)
+	if len(h.entries) > historyMax {
+		h.entries = h.entries[len(h.entries)-historyMax:]
+	}
+
+	return h
+}
+
+// Add records a prompt, skipping blanks and repeats of the most recent one
+func (h *promptHistory) Add(entry string) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry) {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > historyMax {
+		h.entries = h.entries[len(h.entries)-historyMax:]
+	}
+
+	var err error
+	if h.saved >= historyMax {
+		err = h.rewrite()
+	} else {
+		err = h.append(entry)
+	}
+	if err != nil {
+		log.Warn().Err(err).Str("file", h.path).Msg("Failed to save prompt history")
+	}
+}
+
+// append adds the prompt to the end of the history file
+func (h *promptHistory) append(entry string) error {
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintln(f, entry); err != nil {
+		return err
+	}
+	h.saved++
+	return nil
+}
+
+// rewrite replaces the history file with the prompts held, the latest included
+func (h *promptHistory) rewrite() error {
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(h.entries, "\n")+"\n"), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		return err
+	}
+	h.saved = len(h.entries)
+	return nil
+}
+
+// Len returns the number of prompts in the history
+func (h *promptHistory) Len() int {
+	return len(h.entries)
+}
+
+// At returns a prompt, 0 being the most recent
+func (h *promptHistory) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
+
+// replay returns the nth most recent prompt, 1 being the last one
+func (h *promptHistory) replay(n int) (string, error) {
+	if n < 1 || n > h.Len() {
+		return "", fmt.Errorf("no prompt %d in history, %d available", n, h.Len())
+	}
+	return h.At(n - 1), nil
+}
+
+// readPrompt reads an instruction from stdin. When stdin is a terminal the line
+// can be edited and past prompts recalled with the arrow keys.
+func readPrompt(reader *bufio.Reader, history *promptHistory) (string, error) {
+	const prompt = "Instruction: "
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		fmt.Print(prompt)
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		history.Add(line)
+		return strings.TrimSpace(line), nil
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", err
+	}
+	defer term.Restore(fd, state)
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, prompt)
+	t.History = history
+
+	line, err := t.ReadLine()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
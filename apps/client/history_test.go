@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPromptHistoryReplay(t *testing.T) {
+	h := loadHistory(filepath.Join(t.TempDir(), historyFile))
+	for _, p := range []string{"first", "second", "third"} {
+		h.Add(p)
+	}
+
+	tests := []struct {
+		n       int
+		want    string
+		wantErr bool
+	}{
+		{n: 1, want: "third"},
+		{n: 3, want: "first"},
+		{n: 0, wantErr: true},
+		{n: -1, wantErr: true},
+		{n: 4, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := h.replay(tt.n)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("replay(%d) = %q, %v, want %q, error %t", tt.n, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestPromptHistorySkipsRepeats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFile)
+	h := loadHistory(path)
+	for _, p := range []string{"add x", "add x", "  add x\n", "", "   ", "fix y", "add x"} {
+		h.Add(p)
+	}
+
+	want := []string{"add x", "fix y", "add x"}
+	if strings.Join(h.entries, "|") != strings.Join(want, "|") {
+		t.Errorf("entries %q, want %q", h.entries, want)
+	}
+
+	// the file holds the same prompts
+	if got := loadHistory(path).entries; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("saved %q, want %q", got, want)
+	}
+}
+
+func TestPromptHistoryTrimsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFile)
+
+	lines := []string{}
+	for i := 0; i < historyMax+20; i++ {
+		lines = append(lines, fmt.Sprintf("prompt %d", i))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	h := loadHistory(path)
+	if h.Len() != historyMax {
+		t.Fatalf("loaded %d prompts, want %d", h.Len(), historyMax)
+	}
+	h.Add("latest")
+	h.Add("after")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(saved) != historyMax {
+		t.Errorf("history file holds %d prompts, want %d", len(saved), historyMax)
+	}
+	if saved[len(saved)-1] != "after" || saved[len(saved)-2] != "latest" {
+		t.Errorf("history file ends with %q, want the latest prompts", saved[len(saved)-2:])
+	}
+	if saved[0] != fmt.Sprintf("prompt %d", 22) {
+		t.Errorf("history file starts with %q, want the oldest prompts dropped", saved[0])
+	}
+}
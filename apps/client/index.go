@@ -0,0 +1,111 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+// contextIndex maps the files of the context to their keywords, and each
+// keyword back to the files it appears in. Files sharing a keyword depend on
+// each other, the mapper doesn't tell declarations from references. Changed
+// and removed files are patched in place, e.g. by a watcher, rather than
+// walking the tree again. It is safe for concurrent use.
+type contextIndex struct {
+	opts parseOptions
+
+	mu       sync.Mutex
+	keywords map[string][]string
+	// files are the files of each keyword, the edges between them
+	files map[string]map[string]bool
+}
+
+// newContextIndex indexes the files of the context, which are parsed again
+// with opts when updated
+func newContextIndex(ctx ctxtypes.ApplicationContext, opts parseOptions) *contextIndex {
+	x := &contextIndex{opts: opts, keywords: map[string][]string{}, files: map[string]map[string]bool{}}
+	for p, kws := range ctxtypes.Flatten(ctx) {
+		x.set(p, kws)
+	}
+	return x
+}
+
+// UpdateFile parses the added or modified file and replaces its keywords. The
+// index is left unchanged when the file can't be parsed.
+func (x *contextIndex) UpdateFile(path string) error {
+	var kws []string
+	if !x.opts.noKeywords {
+		var err error
+		if kws, err = parseFile(path, x.opts); err != nil {
+			return err
+		}
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.remove(path)
+	x.set(path, kws)
+	return nil
+}
+
+// RemoveFile drops the deleted file and its edges
+func (x *contextIndex) RemoveFile(path string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.remove(path)
+}
+
+// Keywords returns the keywords of the file, and whether it is indexed
+func (x *contextIndex) Keywords(path string) ([]string, bool) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	kws, ok := x.keywords[path]
+	return slices.Clone(kws), ok
+}
+
+// Files returns the sorted files having the keyword
+func (x *contextIndex) Files(keyword string) []string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return slices.Sorted(maps.Keys(x.files[keyword]))
+}
+
+// Dependents returns the sorted files sharing a keyword with the file
+func (x *contextIndex) Dependents(path string) []string {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	dependents := map[string]bool{}
+	for _, k := range x.keywords[path] {
+		for f := range x.files[k] {
+			if f != path {
+				dependents[f] = true
+			}
+		}
+	}
+	return slices.Sorted(maps.Keys(dependents))
+}
+
+// set indexes the keywords of the file, which must not be indexed
+func (x *contextIndex) set(path string, kws []string) {
+	x.keywords[path] = kws
+	for _, k := range kws {
+		if x.files[k] == nil {
+			x.files[k] = map[string]bool{}
+		}
+		x.files[k][path] = true
+	}
+}
+
+// remove drops the file from the keywords it had, and the keywords left without files
+func (x *contextIndex) remove(path string) {
+	for _, k := range x.keywords[path] {
+		delete(x.files[k], path)
+		if len(x.files[k]) == 0 {
+			delete(x.files, k)
+		}
+	}
+	delete(x.keywords, path)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"slices"
+	"testing"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+// walkIndex indexes the tree of the current directory, as it would be after a full walk
+func walkIndex(t *testing.T) *contextIndex {
+	t.Helper()
+	root, _ := walkTree(t, parseOptions{noCache: true})
+	return newContextIndex(ctxtypes.ApplicationContext{FileSystem: map[string]ctxtypes.FileSystemNode{ctxtypes.RootKey: root}}, parseOptions{})
+}
+
+// assertConsistent fails unless the index patched in place matches the index of a full walk
+func assertConsistent(t *testing.T, x *contextIndex) {
+	t.Helper()
+	want := walkIndex(t)
+	if !reflect.DeepEqual(x.keywords, want.keywords) {
+		t.Errorf("got keywords %v, want %v", x.keywords, want.keywords)
+	}
+	if !reflect.DeepEqual(x.files, want.files) {
+		t.Errorf("got edges %v, want %v", x.files, want.files)
+	}
+}
+
+func TestContextIndex(t *testing.T) {
+	dir := tempRepo(t, map[string]string{
+		"store.go":   "package app\n\ntype Store struct{}\n\nfunc (s *Store) Find() {}\n",
+		"handler.go": "package app\n\nfunc Handle(s *Store) { s.Find() }\n",
+	})
+	x := walkIndex(t)
+
+	if got := x.Dependents("handler.go"); !slices.Equal(got, []string{"store.go"}) {
+		t.Fatalf("handler.go has dependents %q", got)
+	}
+
+	// add
+	writeTestFile(t, dir+"/cache.go", "package app\n\ntype Cache struct{ store *Store }\n")
+	if err := x.UpdateFile("cache.go"); err != nil {
+		t.Fatal(err)
+	}
+	assertConsistent(t, x)
+	if got := x.Files("Cache"); !slices.Equal(got, []string{"cache.go"}) {
+		t.Errorf("Cache is in %q", got)
+	}
+	if got := x.Dependents("store.go"); !slices.Equal(got, []string{"cache.go", "handler.go"}) {
+		t.Errorf("store.go has dependents %q", got)
+	}
+
+	// modify, the handler no longer uses the store
+	writeTestFile(t, dir+"/handler.go", "package app\n\nfunc Handle(c *Cache) {}\n")
+	if err := x.UpdateFile("handler.go"); err != nil {
+		t.Fatal(err)
+	}
+	assertConsistent(t, x)
+	if got := x.Files("Find"); !slices.Equal(got, []string{"store.go"}) {
+		t.Errorf("Find is in %q", got)
+	}
+
+	// delete
+	if err := os.Remove("store.go"); err != nil {
+		t.Fatal(err)
+	}
+	x.RemoveFile("store.go")
+	assertConsistent(t, x)
+	if _, ok := x.Keywords("store.go"); ok {
+		t.Error("store.go is still indexed")
+	}
+	if got := x.Files("Find"); len(got) > 0 {
+		t.Errorf("Find is still in %q", got)
+	}
+}
+
+func TestContextIndexUpdateFails(t *testing.T) {
+	tempRepo(t, map[string]string{"store.go": "package app\n\ntype Store struct{}\n"})
+	x := walkIndex(t)
+
+	// a file deleted before its change event is handled
+	if err := x.UpdateFile("gone.go"); err == nil {
+		t.Error("indexed a missing file")
+	}
+	if _, ok := x.Keywords("gone.go"); ok {
+		t.Error("gone.go is indexed")
+	}
+	assertConsistent(t, x)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cyber-nic/ctx/apps/client/mapper"
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+// sourceLanguages names the languages of common source extensions, supported or not
+var sourceLanguages = map[string]string{
+	".go": "go", ".py": "python", ".js": "javascript", ".jsx": "javascript", ".ts": "typescript", ".tsx": "typescript",
+	".rs": "rust", ".java": "java", ".kt": "kotlin", ".scala": "scala", ".cs": "csharp", ".rb": "ruby", ".php": "php",
+	".swift": "swift", ".c": "c", ".h": "c", ".cc": "cpp", ".cpp": "cpp", ".cxx": "cpp", ".hpp": "cpp",
+}
+
+// hasExtractor reports whether keywords can be extracted from the file: its
+// grammar is built in and loaded, and the mapper knows the language
+func hasExtractor(p string) bool {
+	language := getLanguage(p)
+	return language != nil && language.Inner != nil && mapper.LanguageName(p) != ""
+}
+
+// missingExtractors counts the files of the context, by language, whose keywords
+// can't be extracted by this build. Ignored files don't count.
+func missingExtractors(appCtx ctxtypes.ApplicationContext) map[string]int {
+	missing := map[string]int{}
+	ctxtypes.WalkFiles(appCtx, func(p string, node *ctxtypes.FileSystemNode) {
+		if node.Skip && node.SkipReason == "" {
+			return
+		}
+		name, ok := sourceLanguages[strings.ToLower(path.Ext(p))]
+		if ok && !hasExtractor(p) {
+			missing[name]++
+		}
+	})
+	return missing
+}
+
+// formatMissingExtractors describes the languages without an extractor, e.g. "rust (12 files)"
+func formatMissingExtractors(missing map[string]int) string {
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s (%d files)", name, missing[name])
+		if missing[name] == 1 {
+			parts[i] = fmt.Sprintf("%s (1 file)", name)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
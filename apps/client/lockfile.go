@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxLockDependencies bounds the keywords of a lockfile, the direct
+// dependencies of large projects fit, the long tail of a monorepo doesn't
+const maxLockDependencies = 500
+
+// lockParsers extract the pinned dependencies of a lockfile, keyed by its lowercase name
+var lockParsers = map[string]func(data []byte) (map[string]string, error){
+	"go.sum":            goSumDependencies,
+	"package-lock.json": npmLockDependencies,
+	"yarn.lock":         yarnLockDependencies,
+	"cargo.lock":        cargoLockDependencies,
+}
+
+// lockfileKeywords returns the dependencies pinned by the lockfile as
+// name@version keywords, sorted, at most maxLockDependencies of them
+func lockfileKeywords(filePath string) ([]string, error) {
+	parse, ok := lockParsers[strings.ToLower(filepath.Base(filePath))]
+	if !ok {
+		return nil, fmt.Errorf("unsupported lockfile: %s", filePath)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %s", filePath)
+	}
+
+	deps, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile %s: %w", filePath, err)
+	}
+
+	keywords := make([]string, 0, len(deps))
+	for name, version := range deps {
+		keywords = append(keywords, name+"@"+version)
+	}
+	sort.Strings(keywords)
+
+	if len(keywords) > maxLockDependencies {
+		keywords = keywords[:maxLockDependencies]
+	}
+	return keywords, nil
+}
+
+// goSumDependencies reads the module lines of a go.sum, skipping the go.mod hashes
+func goSumDependencies(data []byte) (map[string]string, error) {
+	deps := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		version := strings.TrimSuffix(fields[1], "/go.mod")
+		deps[fields[0]] = version
+	}
+	return deps, scanner.Err()
+}
+
+// npmLockDependencies reads the packages of lockfile v2 and v3, or the dependencies of v1
+func npmLockDependencies(data []byte) (map[string]string, error) {
+	var lock struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	deps := map[string]string{}
+	for p, pkg := range lock.Packages {
+		// "" is the project itself, nested node_modules pin other versions of the same names
+		name, ok := strings.CutPrefix(p, "node_modules/")
+		if !ok || strings.Contains(name, "/node_modules/") || pkg.Version == "" {
+			continue
+		}
+		deps[name] = pkg.Version
+	}
+	if len(lock.Packages) == 0 {
+		for name, dep := range lock.Dependencies {
+			deps[name] = dep.Version
+		}
+	}
+	return deps, nil
+}
+
+// yarnLockDependencies reads the entries of yarn v1 and berry lockfiles, e.g.
+//
+//	"lodash@^4.17.0", lodash@^4.17.21:
+//	  version "4.17.21"
+func yarnLockDependencies(data []byte) (map[string]string, error) {
+	deps := map[string]string{}
+	name := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case strings.HasPrefix(line, "__metadata"):
+			name = ""
+		case !strings.HasPrefix(line, " "):
+			// the first descriptor names the package, scoped names start with @
+			desc := strings.Trim(strings.TrimSpace(strings.SplitN(strings.TrimSuffix(line, ":"), ",", 2)[0]), "\"")
+			name = desc
+			if i := strings.LastIndex(desc, "@"); i > 0 {
+				name = desc[:i]
+			}
+		case name != "" && strings.HasPrefix(strings.TrimSpace(line), "version"):
+			version := strings.TrimPrefix(strings.TrimSpace(line), "version")
+			deps[name] = strings.Trim(strings.TrimSpace(strings.TrimPrefix(version, ":")), "\"")
+			name = ""
+		}
+	}
+	return deps, scanner.Err()
+}
+
+// cargoLockDependencies reads the name and version of each [[package]] of a Cargo.lock
+func cargoLockDependencies(data []byte) (map[string]string, error) {
+	deps := map[string]string{}
+	name := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			if strings.TrimSpace(scanner.Text()) == "[[package]]" {
+				name = ""
+			}
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), "\"")
+		switch strings.TrimSpace(key) {
+		case "name":
+			name = value
+		case "version":
+			if name != "" {
+				deps[name] = value
+			}
+		}
+	}
+	return deps, scanner.Err()
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+// workspaceEdit is an LSP WorkspaceEdit expressed with document changes, which
+// unlike plain changes can also create, rename and delete files. Changes are
+// applied in order.
+type workspaceEdit struct {
+	DocumentChanges []any `json:"documentChanges"`
+}
+
+// textDocumentEdit holds the edits of a single document
+type textDocumentEdit struct {
+	TextDocument versionedDocument   `json:"textDocument"`
+	Edits        []ctxtypes.TextEdit `json:"edits"`
+}
+
+// versionedDocument identifies a document, a null version meaning its content on disk
+type versionedDocument struct {
+	URI     string `json:"uri"`
+	Version *int   `json:"version"`
+}
+
+// resourceOperation is an LSP CreateFile, RenameFile or DeleteFile operation
+type resourceOperation struct {
+	Kind   string `json:"kind"`
+	URI    string `json:"uri,omitempty"`
+	OldURI string `json:"oldUri,omitempty"`
+	NewURI string `json:"newUri,omitempty"`
+}
+
+// fileURI returns the file URI of a path relative to the working directory
+func fileURI(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(path)}
+	return u.String()
+}
+
+func (w *workspaceEdit) create(path string) {
+	w.DocumentChanges = append(w.DocumentChanges, resourceOperation{Kind: "create", URI: fileURI(path)})
+}
+
+func (w *workspaceEdit) rename(from, to string) {
+	w.DocumentChanges = append(w.DocumentChanges, resourceOperation{Kind: "rename", OldURI: fileURI(from), NewURI: fileURI(to)})
+}
+
+func (w *workspaceEdit) delete(path string) {
+	w.DocumentChanges = append(w.DocumentChanges, resourceOperation{Kind: "delete", URI: fileURI(path)})
+}
+
+// edit adds the edits of a document. Edits are rejected when a range is invalid.
+func (w *workspaceEdit) edit(path string, edits []ctxtypes.TextEdit) error {
+	for _, e := range edits {
+		s, end := e.Range.Start, e.Range.End
+		if s.Line < 0 || s.Character < 0 || end.Line < s.Line || (end.Line == s.Line && end.Character < s.Character) {
+			return fmt.Errorf("invalid edit range %d:%d-%d:%d", s.Line, s.Character, end.Line, end.Character)
+		}
+	}
+
+	w.DocumentChanges = append(w.DocumentChanges, textDocumentEdit{
+		TextDocument: versionedDocument{URI: fileURI(path)},
+		Edits:        edits,
+	})
+	return nil
+}
+
+// writeWorkspaceEdit writes the workspace edit as JSON to outFile, or stdout when empty
+func writeWorkspaceEdit(w workspaceEdit, outFile string) error {
+	if w.DocumentChanges == nil {
+		w.DocumentChanges = []any{}
+	}
+
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if outFile == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(outFile, data, 0644)
+}
@@ -2,31 +2,37 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"io/fs"
-	"net/url"
+	"maps"
 	"os"
+	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"syscall"
 	"time"
 
+	ctxexcludes "github.com/cyber-nic/ctx/libs/excludes"
+	ctxignore "github.com/cyber-nic/ctx/libs/ignore"
+	ctxtelemetry "github.com/cyber-nic/ctx/libs/telemetry"
 	ctxtypes "github.com/cyber-nic/ctx/libs/types"
 	ctxutils "github.com/cyber-nic/ctx/libs/utils"
-	"github.com/sergi/go-diff/diffmatchpatch"
 
 	"github.com/cyber-nic/ctx/apps/client/mapper"
 	"github.com/gorilla/websocket"
 	"github.com/rs/zerolog/log"
 	sitter "github.com/tree-sitter/go-tree-sitter"
-)
-
-const (
-	ctxIgnoreFile = ".ctxignore"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/term"
 )
 
 // application entrypoint
@@ -55,12 +61,176 @@ func main() {
 		os.Exit(0)
 	}()
 
-	var addr = flag.String("addr", "localhost:8000", "http service address")
+	// subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "map":
+			runMap(os.Args[2:])
+			return
+		case "apply-bundle":
+			runApplyBundle(os.Args[2:])
+			return
+		case "excludes":
+			runExcludes(os.Args[2:])
+			return
+		case "check-ignore":
+			runCheckIgnore(os.Args[2:])
+			return
+		case "review":
+			runReview(os.Args[2:])
+			return
+		}
+	}
+
+	var addr = flag.String("addr", defaultAddr, "server address: host:port, ws(s)://host or ctx://host (env CTX_ADDR)")
 	var debug = flag.Bool("debug", false, "enable debug mode")
+	var candidates = flag.Int("candidates", 1, "number of alternative patches to request per file")
+	var workConcurrency = flag.Int("work-concurrency", 1, "maximum number of concurrent in-flight work requests")
+	var format = flag.String("format", "files", "output format: 'files' prints each patch, 'patch' emits a single combined patch, 'lsp' emits an LSP WorkspaceEdit without touching files")
+	var apply = flag.Bool("apply", false, "apply each patch to the working tree with git apply, printing those that don't apply. With -format patch, rather than emitting the combined patch")
+	var outFile = flag.String("o", "", "write the combined patch or workspace edit to this file instead of stdout (with -format patch or lsp)")
+	var replay = flag.Int("replay", 0, "re-run the nth most recent prompt from "+historyFile+", 1 being the last")
+	var outDir = flag.String("out-dir", "", "write the patches and a manifest to this directory instead of applying them, see apply-bundle")
+	var maxFileSize = flag.Int64("max-file-size", 1<<20, "skip keyword extraction for files larger than this many bytes, replacing the per-language defaults when set (0 disables)")
+	var maxLines = flag.Int("max-lines", 20000, "skip keyword extraction for files with more lines than this, replacing the per-language defaults when set (0 disables)")
+	var noKeywords = flag.Bool("no-keywords", false, "send the file structure only, skipping keyword extraction for a faster walk")
+	var todos = flag.Bool("todos", false, "send the TODO, FIXME, HACK and XXX comments of each file with their line")
+	var commentKeywords = flag.Bool("comment-keywords", false, "also extract the words of comments and docstrings as keywords")
+	var noCache = flag.Bool("no-cache", false, "parse every file, ignoring the keywords cached in "+keywordCacheFile)
+	var parseConcurrency = flag.Int("parse-concurrency", defaultParseConcurrency, "number of files parsed at once while walking the tree")
+	var chunkSize = flag.Int("chunk-size", 256<<10, "parse files larger than this many bytes in overlapping chunks (0 disables)")
+	var contextFiles stringSliceFlag
+	flag.Var(&contextFiles, "context-file", "always include the full content of this file as context (repeatable)")
+	var contextMaxFileSize = flag.Int64("context-max-file-size", 256<<10, "skip additional context files larger than this many bytes (0 disables)")
+	var contextKeywordsOver = flag.Int64("context-keywords-over", 64<<10, "send the keywords of additional context files larger than this many bytes instead of their content (0 disables)")
+	var contextReadTimeout = flag.Duration("context-read-timeout", 10*time.Second, "deadline for reading all additional context files, or the whole tree with -full-content")
+	var readConcurrency = flag.Int("read-concurrency", defaultReadConcurrency, "number of files read at once")
+	var fullContent = flag.Bool("full-content", false, "send the content of every file of the context, within -full-content-budget")
+	var fullContentBudget = flag.Int64("full-content-budget", 4<<20, "total bytes of file contents sent with -full-content, files are taken in path order (0 disables)")
+	var maxTargetTokens = flag.Int("max-target-tokens", 100000, "skip work targets estimated over this many tokens, line numbers included (0 disables)")
+	var onStale = flag.String("on-stale", onStaleRetry, "when a file changes locally while its changes are requested: 'retry' requests them again against the current content, 'skip' leaves the file out")
+	var minConfidence = flag.Float64("min-confidence", 0, "don't apply patches the model is less confident in than this, from 0 to 1, or that come without a confidence (0 disables)")
+	var reconnectAttempts = flag.Int("reconnect-attempts", 5, "dials to the server before giving up, when connecting or once the connection is lost (1 disables retries)")
+	var reconnectMaxDelay = flag.Duration("reconnect-max-delay", 30*time.Second, "longest wait between dials to the server, the wait doubling from 500ms after each failure")
+	var withTests = flag.Bool("with-tests", false, "also request patches for the test files of edited sources")
+	var docFiles stringSliceFlag
+	flag.Var(&docFiles, "doc", "include this reference document as read-only context under docs/ (repeatable)")
+	ignoreFlags := registerIgnoreFlags(flag.CommandLine)
+	var contextFormat = flag.String("context-format", "tree", "context encoding sent to the server: 'tree' or 'flat'")
+	var stepsFlag = flag.String("steps", defaultSteps, "comma separated steps to run: load, plan, select, work. plan asks for an implementation plan to approve before selecting files")
+	var rootFlag = flag.String("root", "", "repo root the context is built from, detected from .git or go.mod when empty")
+	var redactFlag = flag.String("redact", "", "comma separated parts of file contents replaced with placeholders before sending: strings, comments")
+	var noContents = flag.Bool("no-contents", false, "never send file contents, only keywords and the content of the file being changed")
+	var summarizeOver = flag.Int("summarize-over", 0, "summarize directories with more entries than this, listing their keywords instead of their files (0 disables)")
+	var minFiles = flag.Int("min-files", 3, "warn when the context holds fewer files than this, or no keywords")
+	var force = flag.Bool("force", false, "send a context that looks empty without asking, required when stdin isn't a terminal")
+	var explain = flag.Bool("explain", false, "show the keywords and relevance behind each selected file")
+	var pick = flag.Bool("pick", false, "list the context files and directories to deselect some before the context is sent")
+	var extraInstructions stringSliceFlag
+	flag.Var(&extraInstructions, "instruction", "additional instruction for the model, e.g. \"don't modify the public API\" (repeatable)")
+	var lineFormatFlag = flag.String("line-format", "pipe", "how the lines of the file being changed are numbered for the model: 'pipe' (12 | ), 'bracket' (⟦12⟧ ) or 'none'")
+	var strictLang = flag.Bool("strict-lang", false, "exit when the tree holds source files of a language this build can't extract keywords from")
+	var streamTree = flag.Bool("stream-tree", false, "send the tree to the server while it is walked, overlapping the walk with the upload")
+	var loadChunkSize = flag.Int("load-chunk-size", 256<<10, "send contexts larger than this many bytes in chunks, reporting the upload progress (0 disables)")
+	var streamWork = flag.Bool("stream-work", false, "print the output of the model to stdout as it generates each patch, if the server supports it")
+	var internKeywords = flag.Bool("intern-keywords", false, "send keywords as indexes into a shared table to shrink the context, if the server supports it")
+	var otlpEndpoint = flag.String("otlp-endpoint", "", "export OpenTelemetry traces to this OTLP/HTTP endpoint, e.g. http://localhost:4318 (disabled if empty)")
+	var seedFiles = flag.String("files", "", "comma separated files to work on, required when the select step is skipped")
+	var selectFiles = flag.String("select-files", "", "comma separated files of the context to work on, skipping the select step")
 	flag.Parse()
 
 	ctxutils.ConfigLogging(debug)
 
+	// set when a prompt fails in a way scripts should notice, applied once everything is flushed
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+
+	ctx := context.Background()
+	shutdownTracing, err := ctxtelemetry.Setup(ctx, "ctx-client", *otlpEndpoint)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error setting up tracing")
+	}
+	defer shutdownTracing(ctx)
+
+	// every step of the run is traced under a single root span
+	ctx, span := ctxtelemetry.Tracer().Start(ctx, "ctx")
+	defer span.End()
+
+	steps, err := parseSteps(*stepsFlag)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid -steps")
+	}
+	// -select-files is a local selection, validated against the context once built
+	if *selectFiles != "" {
+		if *seedFiles != "" {
+			log.Fatal().Msg("-files and -select-files can't be used together")
+		}
+		if !steps.work {
+			log.Fatal().Msg("-select-files requires the work step")
+		}
+		steps.selection = false
+		*seedFiles = *selectFiles
+	}
+	if !steps.selection && *seedFiles == "" {
+		log.Fatal().Msg("-files is required when the select step is skipped")
+	}
+	if *apply && (*format == "lsp" || *outFile != "") {
+		log.Fatal().Msg("-apply can't be used with -format lsp or -o")
+	}
+	// concurrent streams would interleave on stdout
+	if *streamWork && *workConcurrency > 1 {
+		log.Fatal().Msg("-stream-work can't be used with -work-concurrency above 1")
+	}
+	if *onStale != onStaleRetry && *onStale != onStaleSkip {
+		log.Fatal().Str("value", *onStale).Msg("-on-stale must be 'retry' or 'skip'")
+	}
+	// a streamed tree is sent as walked, it can't be reshaped before the load
+	if *streamTree && (*pick || *summarizeOver > 0 || *contextFormat != contextFormatTree || *internKeywords) {
+		log.Fatal().Msg("-stream-tree can't be used with -pick, -summarize-over, -intern-keywords or the flat context format")
+	}
+
+	// extra instructions apply to every request of the run, make them visible
+	for _, instruction := range extraInstructions {
+		log.Info().Str("instruction", instruction).Msg("extra instruction")
+	}
+
+	lineFormat, err := ctxtypes.ParseLineFormat(*lineFormatFlag)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid -line-format")
+	}
+
+	redactOpts, err := parseRedact(*redactFlag)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid -redact")
+	}
+	redact := newRedactor(redactOpts)
+
+	if *noContents && (len(contextFiles) > 0 || len(docFiles) > 0 || *fullContent) {
+		log.Fatal().Msg("-context-file, -doc and -full-content send file contents and can't be used with -no-contents")
+	}
+
+	// keywords are interned once the server agreed to it, see the connection below
+	interned := false
+
+	// outgoing applies the privacy and encoding options to a context about to be sent
+	outgoing := func(ctx ctxtypes.ApplicationContext) ctxtypes.ApplicationContext {
+		if *noContents {
+			ctx.FileContents = nil
+		} else {
+			ctx = redact.context(ctx)
+		}
+		if interned {
+			ctx = ctxtypes.EncodeKeywords(ctx)
+		}
+		return ctx
+	}
+
+	cfg := loadConfig()
+
 	// Get the MAC address of the host machine to identify unauthenticated users. Skip if logged in
 	macAddr, err := getMacAddr()
 	if err != nil {
@@ -68,293 +238,772 @@ func main() {
 	}
 	log.Trace().Str("client_id", macAddr).Msg("client")
 
-	// Get the current working directory
-	cwd, err := os.Getwd()
+	// all paths are relative to the repo root, wherever the command is invoked from
+	root, invokedFrom, err := enterRepoRoot(*rootFlag)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Error getting current working directory")
-		return
+		log.Fatal().Err(err).Msg("Error locating repo root")
 	}
+	log.Debug().Str("root", root).Msg("repo root")
 
-	// Load the ignore list
-	// tr@ck - combine .ctxignore with .gitignore
-	ignoreList := loadIgnoreList(filepath.Join(cwd, ctxIgnoreFile))
-
-	rootNode, err := getContextFileTree(cwd, ignoreList)
-	if err != nil {
-		log.Fatal().Err(err).Msg("Error getting folder structure")
+	for i, p := range contextFiles {
+		contextFiles[i] = fromInvocation(root, invokedFrom, p)
+	}
+	for i, p := range docFiles {
+		docFiles[i] = fromInvocation(root, invokedFrom, p)
+	}
+	files := []string{}
+	for _, p := range strings.Split(*seedFiles, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			files = append(files, fromInvocation(root, invokedFrom, p))
+		}
 	}
+	*outFile = absFrom(invokedFrom, *outFile)
+	*outDir = absFrom(invokedFrom, *outDir)
 
-	appCtx := ctxtypes.ApplicationContext{
-		FileSystemDetails: []string{
-			"'Skip' signifies that the file or directory exists, but content is ignored",
-		},
-		FileSystem: rootNode,
+	// the server adapts its guidance to the kind of project
+	projectTypes := []string{}
+	for _, p := range ctxexcludes.Detect(root) {
+		projectTypes = append(projectTypes, p.Name)
 	}
+	log.Info().Strs("project_types", projectTypes).Msg("detected project types")
 
 	// Create channels for coordination
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
 
 	// Setup WebSocket connection
-	wsconn := url.URL{Scheme: "ws", Host: *addr, Path: "/data"}
-	log.Printf("connecting to %s", wsconn.String())
-
-	ws, _, err := websocket.DefaultDialer.Dial(wsconn.String(), nil)
+	wsconn, err := serverURL(resolveAddr(*addr, cfg))
 	if err != nil {
-		log.Fatal().Err(err).Msg("dial")
+		log.Fatal().Err(err).Msg("server address")
 	}
-	defer ws.Close()
 
-	// STEP 1: PRELOAD
-	{
-		// immediately send a message containing the application context so as to cache it on the server / ai
-		msg := ctxtypes.CtxRequest{
-			ClientID: macAddr,
-			Step:     ctxtypes.CtxStepLoadContext,
-			Context:  appCtx,
-		}
+	features := []string{ctxtypes.FeatureChunkedLoad, ctxtypes.FeatureKeepalive}
+	if *streamTree {
+		features = append(features, ctxtypes.FeatureStreamedLoad)
+	}
+	if *internKeywords {
+		features = append(features, ctxtypes.FeatureInternedKeywords)
+	}
 
-		msgData, err := json.Marshal(msg)
+	var ws *websocket.Conn
+	accepted := map[string]bool{}
+	reconnectOpts := reconnectOptions{attempts: *reconnectAttempts, maxDelay: *reconnectMaxDelay}
+	connect := func() error {
+		log.Printf("connecting to %s", wsconn.String())
+		conn, acc, err := dialWithBackoff(ctx, wsconn.String(), features, reconnectOpts)
 		if err != nil {
-			log.Fatal().Err(err).Msg("Error marshalling JSON")
+			return err
+		}
+		ws, accepted = conn, acc
+
+		if *internKeywords {
+			interned = accepted[ctxtypes.FeatureInternedKeywords]
+			if !interned {
+				log.Warn().Msg("Server doesn't support interned keywords, sending them as is")
+			}
 		}
 
-		if err := ws.WriteMessage(websocket.TextMessage, msgData); err != nil {
-			log.Err(err).Msg("write")
+		// older servers only take the context in a single request
+		if !accepted[ctxtypes.FeatureChunkedLoad] {
+			*loadChunkSize = 0
 		}
+		return nil
 	}
 
-	// STEP 2: SELECT
-	var waitForIt atomic.Bool
-	waitForIt.Store(true)
-	userPrompt := ""
+	// the tree is sent as it is walked, its checks below run once it is complete.
+	// Otherwise the server is only reached once the context is known to be worth sending.
+	var streamer *treeStreamer
+	if *streamTree && steps.load {
+		if err := connect(); err != nil {
+			log.Fatal().Err(err).Msg("dial")
+		}
+		if accepted[ctxtypes.FeatureStreamedLoad] {
+			streamer = newTreeStreamer(ctx, ws, macAddr)
+		} else {
+			log.Warn().Msg("Server doesn't support streamed loads, sending the tree once walked")
+		}
+	}
 
-	// Goroutine for reading input
-	reader := bufio.NewReader(os.Stdin)
-	for waitForIt.Load() {
-		fmt.Printf("Instruction: ")
-		userPrompt, err := reader.ReadString('\n')
+	parseOpts := parseOptions{maxFileSize: *maxFileSize, maxLines: *maxLines, chunkSize: *chunkSize, noKeywords: *noKeywords, todos: *todos, comments: *commentKeywords, concurrency: *parseConcurrency, noCache: *noCache}
+	parseOpts.languages = resolveLimits(cfg, parseOpts, isFlagSet("max-file-size"), isFlagSet("max-lines"))
+	if streamer != nil {
+		parseOpts.stream = streamer.add
+	}
 
-		if err != nil {
-			waitForIt.Store(false)
-			log.Error().Err(err).Msg("Error reading input")
-			return
-		}
+	_, walkSpan := ctxtelemetry.Tracer().Start(ctx, "walk")
+	appCtx, summarized, err := buildAppContext(root, ignoreFlags.options(), parseOpts, *contextFormat, *summarizeOver)
+	if err == nil && streamer != nil {
+		err = streamer.flush()
+	}
+	walkSpan.End()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error getting folder structure")
+	}
 
-		userPrompt = strings.TrimSpace(userPrompt)
-		if userPrompt == "" {
-			continue
+	// a language without a grammar silently contributes no keywords
+	if missing := missingExtractors(appCtx); len(missing) > 0 && !*noKeywords {
+		languages := formatMissingExtractors(missing)
+		if *strictLang {
+			log.Fatal().Str("languages", languages).Msg("No keyword extractor for languages of the tree")
 		}
+		log.Warn().Str("languages", languages).Msg("No keyword extractor for languages of the tree")
+		fmt.Fprintf(os.Stderr, "WARNING: no keywords are extracted from %s, this build has no grammar for them\n", languages)
+	}
 
-		waitForIt.Store(false)
-		log.Info().Str("value", userPrompt).Msg("input")
+	reader := bufio.NewReader(os.Stdin)
 
-		// send the app context with the user prompt
-		msg := ctxtypes.CtxRequest{
-			ClientID:   macAddr,
-			Step:       ctxtypes.CtxStepFileSelection,
-			Context:    appCtx,
-			UserPrompt: userPrompt,
+	// one-off scoping of the context, without editing .ctxignore
+	if *pick {
+		excluded, err := pickContext(reader, appCtx)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Error reading picked entries")
 		}
+		pruneContext(&appCtx, excluded)
+	}
 
-		msgData, err := json.Marshal(msg)
-		if err != nil {
-			log.Fatal().Err(err).Msg("Error marshalling JSON")
+	// catch aggressive ignore rules or a wrong directory before anything is sent
+	if warning := contextWarning(appCtx, *minFiles, !*noKeywords); warning != "" {
+		log.Warn().Str("root", root).Msg("Context looks empty")
+		fmt.Fprintf(os.Stderr, "WARNING: %s\n", warning)
+		if !*force {
+			if !term.IsTerminal(int(os.Stdin.Fd())) {
+				log.Fatal().Msg("Refusing to send a near empty context, use -force to send it anyway")
+			}
+			if !confirm(reader, "Send it anyway? [y/N]: ") {
+				exitCode = 1
+				return
+			}
 		}
+	}
 
-		// Send the payload to the server
-		if err := ws.WriteMessage(websocket.TextMessage, msgData); err != nil {
-			log.Err(err).Msg("write")
-			return
+	if *selectFiles != "" {
+		if missing := missingFiles(appCtx, summarized, files); len(missing) > 0 {
+			log.Fatal().Strs("files", missing).Msg("-select-files lists files that are not in the context")
 		}
 	}
 
-	// Unmarshal to StepFileSelectResponseSchema
-	var selectResp ctxtypes.StepFileSelectResponseSchema
+	if *contextKeywordsOver > 0 {
+		appCtx.FileSystemDetails = append(appCtx.FileSystemDetails,
+			"'file_keywords' maps additional context files too large to be sent to their keywords. Their content is not provided, rely on the keywords for their structure")
+	}
 
-	waitForIt.Store(true)
+	// the whole tree is sent, pinned files and documents are added on top
+	if *fullContent {
+		_, readSpan := ctxtelemetry.Tracer().Start(ctx, "read")
+		appCtx.FileContents = readFullContent(appCtx, *fullContentBudget, *contextMaxFileSize, *readConcurrency, *contextReadTimeout)
+		readSpan.End()
+		appCtx.FileSystemDetails = append(appCtx.FileSystemDetails,
+			"'file_contents' holds the content of the files of the context, except binary files and those left out by a size budget")
+	}
 
-	// fetch files to update
-	for waitForIt.Load() {
-		_, message, err := ws.ReadMessage()
-		waitForIt.Store(false)
+	// pinned files are always sent in full
+	for _, p := range contextFiles {
+		content, err := os.ReadFile(p)
+		if err != nil {
+			log.Fatal().Err(err).Str("file", p).Msg("Error reading context file")
+		}
+		appCtx.FileContents[p] = string(content)
+		appCtx.Pinned = append(appCtx.Pinned, p)
+	}
 
+	// reference documents are grounding only and can never be edited
+	for _, p := range docFiles {
+		content, err := os.ReadFile(p)
 		if err != nil {
-			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
-				log.Info().Msg("Connection closed by server")
-			} else {
-				log.Err(err).Msg("Error reading message")
-			}
-			return
+			log.Fatal().Err(err).Str("file", p).Msg("Error reading doc file")
 		}
+		docPath := docVirtualPath(p)
+		appCtx.FileContents[docPath] = string(content)
+		appCtx.References = append(appCtx.References, docPath)
+	}
 
-		if err := json.Unmarshal(message, &selectResp); err != nil {
-			log.Err(err).Msg("Error unmarshalling JSON")
-			return
+	if ws == nil {
+		if err := connect(); err != nil {
+			log.Fatal().Err(err).Msg("dial")
+		}
+	}
+	// ws is replaced when the connection is lost
+	defer func() { closeConn(ws) }()
+
+	// load sends a message containing the application context so as to cache it on the server / ai
+	load := func(streamed bool) error {
+		msg := ctxtypes.CtxRequest{
+			ClientID: macAddr,
+			Step:     ctxtypes.CtxStepLoadContext,
+			Context:  outgoing(appCtx),
+		}
+		// the server already assembled the streamed tree
+		if streamed {
+			msg.Context.FileSystem = nil
 		}
 
-		// ctxutils.PrintStructOut(selectResp)
+		loadCtx, loadSpan := ctxtelemetry.Tracer().Start(ctx, "load")
+		defer loadSpan.End()
+		return sendContext(loadCtx, ws, msg, *loadChunkSize)
+	}
 
-		for _, file := range selectResp.Data.Files {
-			op := "update"
-			if file.Operation == ctxtypes.FileOperationCreate {
-				op = "create"
-			} else if file.Operation == ctxtypes.FileOperationRemove {
-				op = "remove"
+	// resume reports whether err is a lost connection that was replaced, the
+	// context being loaded again since the server may have restarted
+	resume := func(err error) bool {
+		if !connectionLost(err) {
+			return false
+		}
+		log.Warn().Err(err).Msg("Connection lost, reconnecting")
+		closeConn(ws)
+		if err := connect(); err != nil {
+			log.Err(err).Msg("Unable to reconnect")
+			return false
+		}
+		if steps.load {
+			if err := load(false); err != nil {
+				log.Err(err).Msg("Unable to load context on the server")
+				return false
 			}
+		}
+		return true
+	}
 
-			fmt.Printf("%s | %s: %s\n", op, file.Path, file.Reason)
+	// STEP 1: PRELOAD
+	if steps.load {
+		if err := load(streamer != nil); err != nil {
+			log.Fatal().Err(err).Msg("Unable to load context on the server")
 		}
+	}
+
+	// the contents every prompt starts from, files read for a prompt are not carried over
+	baseContents := maps.Clone(appCtx.FileContents)
 
-		for _, file := range selectResp.Data.Additional {
-			fmt.Printf("+ %s: %s\n", file.Path, file.Reason)
+	replayed := ""
+	history := loadHistory(filepath.Join(root, historyFile))
+	if *replay > 0 {
+		replayed, err = history.replay(*replay)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Unable to replay prompt")
 		}
+		fmt.Printf("Instruction: %s\n", replayed)
+		history.Add(replayed)
 	}
 
-	// STEP 4: WORK
+	// each prompt reuses the context loaded on the server, until the input ends
+session:
+	for {
+		appCtx.FileContents = maps.Clone(baseContents)
+		appCtx.FileKeywords = nil
 
-	// create list of file contents requested by the server
-	appCtx.FileContents = map[string]string{}
+		// a replayed prompt runs first
+		userPrompt := replayed
+		replayed = ""
 
-	{
-		// include create and update files
-		for _, file := range selectResp.Data.Files {
-			if file.Operation != ctxtypes.FileOperationUpdate {
-				continue
+		// the implementation plan approved by the user, if requested
+		var plan *ctxtypes.StepPlanData
+
+		// spans the select request and response, a no-op until the request is sent
+		selectSpan := trace.SpanFromContext(context.Background())
+
+		// STEP 2: SELECT
+		var waitForIt atomic.Bool
+		waitForIt.Store(true)
+		for waitForIt.Load() {
+			if userPrompt == "" {
+				userPrompt, err = readPrompt(reader, history)
+				if errors.Is(err, io.EOF) {
+					break session
+				}
+				if err != nil {
+					log.Error().Err(err).Msg("Error reading input")
+					break session
+				}
 			}
 
-			// read the file contents
-			content, err := os.ReadFile(file.Path)
-			if err != nil {
-				log.Err(err).Msg("Error reading file")
+			if userPrompt == "" {
 				continue
 			}
-			appCtx.FileContents[file.Path] = string(content)
 
+			waitForIt.Store(false)
+			log.Info().Str("value", userPrompt).Msg("input")
+
+			// review the plan before anything is selected or changed
+			if steps.plan {
+				p, err := requestPlan(ctx, ws, ctxtypes.CtxRequest{
+					ClientID:   macAddr,
+					Step:       ctxtypes.CtxStepPlan,
+					Context:    outgoing(appCtx),
+					NoContents: *noContents,
+					UserPrompt: userPrompt,
+					Hints:      extractPathHints(userPrompt, appCtx),
+
+					ExtraInstructions: extraInstructions,
+				})
+				if err != nil {
+					// the prompt runs again on the new connection
+					if resume(err) {
+						replayed = userPrompt
+						continue session
+					}
+					log.Err(err).Msg("Error requesting plan")
+					break session
+				}
+
+				printPlan(p)
+				if !confirmPlan(reader) {
+					fmt.Println("Plan rejected")
+					continue session
+				}
+				plan = &p
+			}
+
+			if !steps.selection {
+				break
+			}
+
+			// send the app context with the user prompt
+			msg := ctxtypes.CtxRequest{
+				ClientID:   macAddr,
+				Step:       ctxtypes.CtxStepFileSelection,
+				Context:    outgoing(appCtx),
+				NoContents: *noContents,
+				UserPrompt: userPrompt,
+				Hints:      extractPathHints(userPrompt, appCtx),
+				Plan:       plan,
+				NoKeywords: *noKeywords,
+				Explain:    *explain,
+
+				ExtraInstructions: extraInstructions,
+			}
+			log.Debug().Strs("hints", msg.Hints).Msg("files mentioned in prompt")
+
+			// Send the payload to the server, there is no response to wait for if this fails
+			var selectCtx context.Context
+			selectCtx, selectSpan = ctxtelemetry.Tracer().Start(ctx, "select")
+			if err := sendRequest(selectCtx, ws, msg); err != nil {
+				if resume(err) {
+					selectSpan.End()
+					replayed = userPrompt
+					continue session
+				}
+				log.Fatal().Err(err).Msg("Unable to request file selection")
+			}
+		}
+
+		// Unmarshal to StepFileSelectResponseSchema
+		var selectResp ctxtypes.StepFileSelectResponseSchema
+
+		// without the select step the files to work on are provided
+		waitForIt.Store(steps.selection)
+		if !steps.selection {
+			selectResp.Data = seedSelection(files)
 		}
-		// include additional context files
-		for _, file := range selectResp.Data.Additional {
-			// read the file contents
-			content, err := os.ReadFile(file.Path)
+
+		// fetch files to update
+		for waitForIt.Load() {
+			message, err := readResponse(ws)
+			waitForIt.Store(false)
+
 			if err != nil {
-				log.Err(err).Msg("Error reading file")
-				continue
+				if resume(err) {
+					selectSpan.End()
+					replayed = userPrompt
+					continue session
+				}
+				if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+					log.Info().Msg("Connection closed by server")
+				} else {
+					log.Err(err).Msg("Error reading message")
+				}
+				break session
+			}
+
+			if err := json.Unmarshal(message, &selectResp); err != nil {
+				log.Err(err).Msg("Error unmarshalling JSON")
+				continue session
 			}
-			appCtx.FileContents[file.Path] = string(content)
+
+			// ctxutils.PrintStructOut(selectResp)
 		}
-	}
 
-	// request individual file changes
-	for _, file := range selectResp.Data.Files {
+		selectSpan.End()
+		printSelection(selectResp.Data)
+		logBudget(selectResp.Budget)
 
-		// create a new version of the file
-		fileContentWithLineNumbers := fmt.Sprintf("# %s\n\n", file.Path)
+		// nothing to work on, the prompt likely needs rephrasing
+		if steps.selection && len(selectResp.Data.Files) == 0 {
+			log.Warn().Str("reason", selectResp.Data.Reason).Msg("Empty file selection")
+			fmt.Println("no files identified for this change; try rephrasing")
+			exitCode = 1
+			continue
+		}
 
-		// add line numbers to the file content
-		if file.Operation == ctxtypes.FileOperationUpdate {
-			// read the file line by line and create a new version where each line is prefixed with the line number
-			fileContents, err := os.ReadFile(file.Path)
-			if err != nil {
-				log.Err(err).Msg("Error reading file")
-				continue
+		// stop after the selection when the work step is skipped
+		if !steps.work {
+			continue
+		}
+
+		// reference documents are never edit targets
+		selectResp.Data.Files = withoutReferences(selectResp.Data.Files, appCtx.References)
+		selectResp.Data.Additional = withoutReferences(selectResp.Data.Additional, appCtx.References)
+
+		// STEP 4: WORK
+
+		// the lsp format leaves all changes, moves included, to the editor
+		lsp := *format == "lsp"
+
+		// files are only moved by the modes changing the working tree, the
+		// patches emitted otherwise carry the moves as renames
+		moveLocally := !lsp && *outDir == "" && (*format == "files" || *apply)
+
+		// sourcePath is where the current content of a selected file is read from
+		sourcePath := func(file ctxtypes.StepFileSelectItem) string {
+			if moveLocally {
+				return file.TargetPath()
 			}
+			return file.Path
+		}
 
-			scanner := bufio.NewScanner(strings.NewReader(string(fileContents)))
-			lineNumber := 1
-			for scanner.Scan() {
-				fileContentWithLineNumbers += fmt.Sprintf("%d | %s\n", lineNumber, scanner.Text())
-				lineNumber++
+		// perform moves first so that history is preserved and edits target the new path
+		for _, file := range selectResp.Data.Files {
+			if file.Operation != ctxtypes.FileOperationMove || !moveLocally {
+				continue
+			}
+			if err := moveFile(file.Path, file.NewPath); err != nil {
+				log.Err(err).Str("from", file.Path).Str("to", file.NewPath).Msg("Error moving file")
 			}
 		}
 
-		// fmt.Println(fileContentWithLineNumbers)
+		// add file contents requested by the server, alongside pinned files
 
-		// request, wait and print changes
-		msg := ctxtypes.CtxRequest{
-			ClientID:   macAddr,
-			Step:       ctxtypes.CtxStepCodeWork,
-			Context:    appCtx,
-			UserPrompt: userPrompt,
-			WorkPrompt: fileContentWithLineNumbers,
+		{
+			// include update and move files
+			for _, file := range selectResp.Data.Files {
+				if file.Operation != ctxtypes.FileOperationUpdate && file.Operation != ctxtypes.FileOperationMove {
+					continue
+				}
+
+				// read the file contents
+				content, err := os.ReadFile(sourcePath(file))
+				if err != nil {
+					log.Err(err).Msg("Error reading file")
+					continue
+				}
+				appCtx.FileContents[file.TargetPath()] = string(content)
+
+			}
+			// include additional context files, unless contents are never sent
+			additional := []string{}
+			for _, file := range selectResp.Data.Additional {
+				// a summarized directory stands for the files it holds
+				if files, ok := summarized[strings.TrimSuffix(file.Path, "/")]; ok {
+					additional = append(additional, files...)
+					continue
+				}
+				additional = append(additional, file.Path)
+			}
+			if !*noContents {
+				// large files are outlined by their keywords to keep the work context small
+				small, large := splitBySize(additional, *contextKeywordsOver)
+				for p, content := range readFiles(small, *contextMaxFileSize, *readConcurrency, *contextReadTimeout) {
+					appCtx.FileContents[p] = content
+				}
+				if len(large) > 0 {
+					appCtx.FileKeywords = fileKeywords(appCtx, large, parseOpts)
+				}
+			}
 		}
 
-		msgData, err := json.Marshal(msg)
-		if err != nil {
-			log.Fatal().Err(err).Msg("Error marshalling JSON")
+		// upload contents the server doesn't have yet and reference them by hash in work requests
+		workCtx := outgoing(appCtx)
+		if !*noContents {
+			if hashes, err := uploadContents(ctx, ws, macAddr, workCtx.FileContents); err != nil {
+				log.Warn().Err(err).Msg("Content upload failed, sending contents inline")
+			} else {
+				workCtx.FileContents = nil
+				workCtx.FileHashes = hashes
+			}
 		}
 
-		if err := ws.WriteMessage(websocket.TextMessage, msgData); err != nil {
-			log.Err(err).Msg("write")
+		// request individual file changes
+		jobs := []ctxtypes.CtxRequest{}
+		paths := []string{}
+		// sources are where the work targets are read from, their path before a move not done yet
+		sources := []string{}
+		ops := []ctxtypes.FileOperation{}
+		// bases are the content hashes of the work targets as sent, empty for new files
+		bases := []string{}
+		workspace := workspaceEdit{}
+		for _, file := range selectResp.Data.Files {
+			// moved files are edited at their new location
+			path := file.TargetPath()
+
+			// resource operations precede the edits of the files they affect
+			if lsp {
+				switch file.Operation {
+				case ctxtypes.FileOperationRemove:
+					workspace.delete(path)
+					continue
+				case ctxtypes.FileOperationCreate:
+					workspace.create(path)
+				case ctxtypes.FileOperationMove:
+					workspace.rename(file.Path, file.NewPath)
+				}
+			}
+
+			target := &ctxtypes.WorkTarget{Path: path, Operation: file.Operation}
+			base := ""
+
+			// include the current content of existing files
+			if file.Operation == ctxtypes.FileOperationUpdate || file.Operation == ctxtypes.FileOperationMove {
+				fileContents, err := os.ReadFile(sourcePath(file))
+				if err != nil {
+					log.Err(err).Msg("Error reading file")
+					continue
+				}
+				content, ok := redact.redact(path, string(fileContents))
+				if !ok {
+					log.Warn().Str("file", path).Msg("Unable to redact, skipping")
+					continue
+				}
+				target.Content = content
+				base = ctxtypes.ContentHash(string(fileContents))
+
+				// a target this large would blow the model's context window and fail opaquely
+				if tokens := estimateTargetTokens(content, lineFormat); *maxTargetTokens > 0 && tokens > *maxTargetTokens {
+					log.Warn().Str("file", path).Int("tokens", tokens).Int("max", *maxTargetTokens).Msg("Work target too large, skipping")
+					fmt.Printf("skipping %s: about %d tokens, over -max-target-tokens %d. Split the file or raise the limit\n", path, tokens, *maxTargetTokens)
+					continue
+				}
+
+				// the only content that leaves the machine, make it visible
+				if *noContents {
+					log.Info().Str("file", path).Msg("Sending the content of the work target")
+				}
+			}
+
+			job := ctxtypes.CtxRequest{
+				ClientID:   macAddr,
+				Step:       ctxtypes.CtxStepCodeWork,
+				Context:    workCtx,
+				UserPrompt: userPrompt,
+				WorkTarget: target,
+				Candidates: *candidates,
+				WithTests:  *withTests,
+				NoContents: *noContents,
+				Plan:       plan,
+				LineFormat: lineFormat,
+				Stream:     *streamWork,
+
+				ExtraInstructions: extraInstructions,
+				ProjectTypes:      projectTypes,
+				Personas:          cfg.Personas,
+			}
+			// candidates and tests only apply to patches
+			if lsp {
+				job.WorkFormat = ctxtypes.WorkFormatEdits
+				job.Candidates = 0
+				job.WithTests = false
+			}
+			jobs = append(jobs, job)
+			paths = append(paths, path)
+			sources = append(sources, sourcePath(file))
+			ops = append(ops, file.Operation)
+			bases = append(bases, base)
 		}
 
-		// Unmarshal to StepFileSelectResponseSchema
-		var workResp ctxtypes.StepFileWorkResponseSchema
+		combined := []string{}
+
+		// applyPatch applies the normalized patch with -apply and reports whether it
+		// was, the caller prints the patches that weren't so that nothing is lost
+		applied, failed := 0, 0
+		applyPatch := func(path, patch string, data ctxtypes.PatchData, lowConfidence bool) bool {
+			reason := ""
+			if lowConfidence {
+				reason = fmt.Sprintf("confidence %s is below -min-confidence %g", formatConfidence(data.Confidence), *minConfidence)
+			} else if err := applyFilePatch(patch); err != nil {
+				log.Err(err).Str("file", path).Msg("Error applying patch")
+				reason, _, _ = strings.Cut(err.Error(), "\n")
+			}
 
-		waitForIt.Store(true)
+			if reason == "" {
+				applied++
+				fmt.Printf("applied %s\n", path)
+				return true
+			}
+			failed++
+			fmt.Printf("# not applied %s: %s\n", path, reason)
+			return false
+		}
 
-		// fetch files to update
-		for waitForIt.Load() {
-			_, message, err := ws.ReadMessage()
-			waitForIt.Store(false)
+		// emitPatch aggregates the patch when emitting a combined patch, or prints and applies it
+		bundle := patchBundle{}
+		emitPatch := func(path, from string, op ctxtypes.FileOperation, data ctxtypes.PatchData, original string) {
+			lowConfidence := belowConfidence(data, *minConfidence)
+			if lowConfidence {
+				log.Warn().Str("file", path).Str("confidence", formatConfidence(data.Confidence)).Strs("assumptions", data.Assumptions).Msg("Low confidence patch, review it closely")
+			}
 
-			if err != nil {
-				if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
-					log.Info().Msg("Connection closed by server")
-				} else {
-					log.Err(err).Msg("Error reading message")
+			if *format == "patch" || *outDir != "" {
+				p, err := normalizePatch(path, from, op, data.Patch)
+				if err != nil {
+					log.Err(err).Str("file", path).Msg("Error normalizing patch")
+					return
+				}
+				if *outDir != "" {
+					bundle.add(path, from, op, p, original, data)
+				}
+				if *apply {
+					if !applyPatch(path, p, data, lowConfidence) {
+						fmt.Print(p)
+					}
+				} else if *format == "patch" {
+					combined = append(combined, p)
 				}
 				return
 			}
 
-			if err := json.Unmarshal(message, &workResp); err != nil {
-				log.Err(err).Msg("Error unmarshalling JSON")
+			fmt.Printf("# %s\n", path)
+			for _, line := range confidenceNote(data) {
+				fmt.Printf("# %s\n", line)
+			}
+			fmt.Println(data.Patch)
+
+			if !*apply {
+				return
+			}
+			p, err := normalizePatch(path, from, op, data.Patch)
+			if err != nil {
+				log.Err(err).Str("file", path).Msg("Error normalizing patch")
+				failed++
+				fmt.Printf("# not applied %s: %v\n", path, err)
 				return
 			}
+			applyPatch(path, p, data, lowConfidence)
+		}
+
+		// the server's token budget as of the last work response
+		var budget *ctxtypes.TokenBudget
+
+		// handleResult prints or applies the changes of jobs[i]. It reports a target
+		// edited since its content was sent as stale, leaving its changes out.
+		handleResult := func(i int, res *workResult) (stale bool) {
+			<-res.done
+			path := paths[i]
 
-			fmt.Printf("# %s\n", file.Path)
-			fmt.Println(workResp.Data.Patch)
+			if res.err != nil {
+				log.Err(res.err).Str("file", path).Msg("Error requesting changes")
+				return false
+			}
 
-			// get folder from file path
-			folder := filepath.Dir(file.Path)
-			// create folder if it doesn't exist
-			if _, err := os.Stat(folder); os.IsNotExist(err) {
-				if err := os.MkdirAll(folder, 0755); err != nil {
-					log.Err(err).Str("folder", folder).Msg("Error creating folder")
+			// a patch against stale content would undo the local edits
+			if !lsp && bases[i] != "" {
+				current, err := os.ReadFile(sources[i])
+				if err != nil || ctxtypes.ContentHash(string(current)) != bases[i] {
+					log.Warn().Str("file", path).Msg("File changed since its content was sent")
+					return true
 				}
 			}
+			workResp := redact.restoreResponse(res.resp)
+			stripResponseDecoration(&workResp, lineFormat)
+			if workResp.Budget != nil {
+				budget = workResp.Budget
+			}
 
-			if err := os.WriteFile(fmt.Sprintf("%s.gitdiff", file.Path), []byte(workResp.Data.Patch), 0644); err != nil {
-				log.Err(err).Str("file", file.Path).Msg("Error writing diff file")
+			if lsp {
+				if err := workspace.edit(path, workResp.Edits); err != nil {
+					log.Err(err).Str("file", path).Msg("Error adding edits")
+				}
+				return false
 			}
 
-			// HACK
-			// remove first two lines from the workResp.Data.Patch
-			minusTwo := strings.Split(workResp.Data.Patch, "\n")[2:]
-			minusTwoStr := strings.Join(minusTwo, "\n")
+			if len(workResp.Candidates) > 1 {
+				workResp.Data = selectCandidate(reader, path, workResp.Candidates)
+			}
 
-			// Parse the patch
-			dmp := diffmatchpatch.New()
-			patches, err := dmp.PatchFromText(minusTwoStr)
-			if err != nil {
-				log.Err(err).Str("file", file.Path).Msg("Error parsing patch")
-				continue
+			emitPatch(path, sources[i], ops[i], workResp.Data, appCtx.FileContents[path])
+
+			// suggested tests target files that may not exist yet, they share the confidence of the change they cover
+			for _, t := range workResp.Tests {
+				op := ctxtypes.FileOperationCreate
+				original, err := os.ReadFile(t.Path)
+				if err == nil {
+					op = ctxtypes.FileOperationUpdate
+				}
+				t.Confidence = workResp.Data.Confidence
+				emitPatch(t.Path, t.Path, op, t, string(original))
+			}
+			return false
+		}
+
+		// request, wait and print changes in selection order
+		stale := []int{}
+		for i, res := range runWork(ctx, &ws, wsconn.String(), features, jobs, *workConcurrency, reconnectOpts) {
+			if handleResult(i, res) {
+				stale = append(stale, i)
+			}
+		}
+
+		// changes to files edited meanwhile are requested again once, against their current content
+		if len(stale) > 0 && *onStale == onStaleRetry {
+			retried := []int{}
+			retryJobs := []ctxtypes.CtxRequest{}
+			for _, i := range stale {
+				content, err := os.ReadFile(sources[i])
+				if err != nil {
+					log.Err(err).Str("file", paths[i]).Msg("Error reading file")
+					continue
+				}
+				redacted, ok := redact.redact(paths[i], string(content))
+				if !ok {
+					log.Warn().Str("file", paths[i]).Msg("Unable to redact, skipping")
+					continue
+				}
+
+				target := *jobs[i].WorkTarget
+				target.Content = redacted
+				jobs[i].WorkTarget = &target
+				bases[i] = ctxtypes.ContentHash(string(content))
+				appCtx.FileContents[paths[i]] = string(content)
+
+				log.Info().Str("file", paths[i]).Msg("Requesting changes again against the current content")
+				retried = append(retried, i)
+				retryJobs = append(retryJobs, jobs[i])
 			}
 
-			// Apply the patch
-			patchedStr, results := dmp.PatchApply(patches, appCtx.FileContents[file.Path])
-			for _, result := range results {
-				if !result {
-					log.Warn().Str("file", file.Path).Msg("Patch failed")
+			stale = []int{}
+			for k, res := range runWork(ctx, &ws, wsconn.String(), features, retryJobs, *workConcurrency, reconnectOpts) {
+				if handleResult(retried[k], res) {
+					stale = append(stale, retried[k])
 				}
 			}
+		}
+		for _, i := range stale {
+			fmt.Printf("skipping %s: changed since its content was sent, its changes would undo the edits\n", paths[i])
+		}
+
+		logBudget(budget)
+
+		if *apply {
+			log.Info().Int("applied", applied).Int("failed", failed).Msg("Patches applied")
+			if failed > 0 {
+				exitCode = 1
+			}
+		}
 
-			// Write the patched content back to the file
-			if err := os.WriteFile(file.Path, []byte(patchedStr), 0644); err != nil {
-				log.Err(err).Str("file", file.Path).Msg("Error writing file")
+		if *outDir != "" {
+			if err := bundle.write(*outDir, userPrompt); err != nil {
+				log.Fatal().Err(err).Msg("Error writing patch bundle")
 			}
+			log.Info().Str("dir", *outDir).Int("patches", len(bundle.patches)).Msg("Patch bundle written")
+		}
 
+		if *format == "patch" && !*apply {
+			if err := writeCombinedPatch(combined, *outFile); err != nil {
+				log.Fatal().Err(err).Msg("Error writing combined patch")
+			}
 		}
 
+		if lsp {
+			if err := writeWorkspaceEdit(workspace, *outFile); err != nil {
+				log.Fatal().Err(err).Msg("Error writing workspace edit")
+			}
+		}
 	}
 
 	// Close channels
@@ -363,7 +1012,79 @@ func main() {
 	log.Info().Msg("Graceful termination")
 }
 
-func parseFile(filePath string) ([]string, error) {
+// moveFile renames a file using git to preserve history, falling back to a plain rename
+func moveFile(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+
+	if out, err := exec.Command("git", "mv", from, to).CombinedOutput(); err != nil {
+		log.Debug().Err(err).Str("output", string(out)).Msg("git mv failed, renaming")
+		return os.Rename(from, to)
+	}
+
+	return nil
+}
+
+// selectCandidate prints each candidate patch and prompts the user to pick one
+func selectCandidate(reader *bufio.Reader, path string, candidates []ctxtypes.PatchData) ctxtypes.PatchData {
+	for i, c := range candidates {
+		fmt.Printf("## %s [candidate %d/%d, confidence %s]\n", path, i+1, len(candidates), formatConfidence(c.Confidence))
+		fmt.Println(c.Patch)
+	}
+
+	for {
+		fmt.Printf("Select candidate [1-%d]: ", len(candidates))
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			log.Warn().Err(err).Msg("Error reading input, using first candidate")
+			return candidates[0]
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(input))
+		if err != nil || n < 1 || n > len(candidates) {
+			continue
+		}
+		return candidates[n-1]
+	}
+}
+
+// parseOptions bounds the files parseFile extracts keywords from and how large
+// files are parsed. Zero values disable an option.
+type parseOptions struct {
+	maxFileSize int64
+	maxLines    int
+	chunkSize   int
+	// languages replaces maxFileSize and maxLines for the files of a language, see resolveLimits
+	languages map[string]mapper.Limits
+	// noKeywords builds a structure-only context, files are never parsed
+	noKeywords bool
+	// todos collects the TODO comments of each file, see fileTodos
+	todos bool
+	// comments adds the words of comments and docstrings to the keywords
+	comments bool
+	// stream, when set, is called with each node of the tree once walked, see treeStreamer
+	stream func(path string, node ctxtypes.FileSystemNode) error
+	// concurrency is the number of files parsed at once, see parseFiles
+	concurrency int
+	// noCache parses every file, ignoring the keywords cached by previous runs
+	noCache bool
+	// cache holds the keywords of the files parsed by previous runs, see keywordCache
+	cache *keywordCache
+	// parsed, when set, is called with each file handed to the parser, cache hits aside
+	parsed func(path string)
+}
+
+// skipError signals that a file was deliberately not parsed
+type skipError struct {
+	reason string
+}
+
+func (e *skipError) Error() string {
+	return e.reason
+}
+
+func parseFile(filePath string, opts parseOptions) ([]string, error) {
 	filePath = strings.Replace(filePath, "./", "", 1)
 
 	language := getLanguage(filePath)
@@ -372,18 +1093,73 @@ func parseFile(filePath string) ([]string, error) {
 		return nil, fmt.Errorf("unsupported file: %s", filePath)
 	}
 
+	// the extension is mapped but the grammar failed to load
+	if language.Inner == nil {
+		return nil, &skipError{reason: "grammar unavailable"}
+	}
+
+	if l, ok := opts.languages[mapper.LanguageName(filePath)]; ok {
+		opts.maxFileSize, opts.maxLines = l.MaxFileSize, l.MaxLines
+	}
+
+	if opts.maxFileSize > 0 {
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat file: %s", filePath)
+		}
+		if info.Size() > opts.maxFileSize {
+			return nil, &skipError{reason: fmt.Sprintf("file size %d exceeds %d bytes", info.Size(), opts.maxFileSize)}
+		}
+	}
+
 	code, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %s", filePath)
 	}
 
+	if opts.maxLines > 0 {
+		if lines := bytes.Count(code, []byte("\n")) + 1; lines > opts.maxLines {
+			return nil, &skipError{reason: fmt.Sprintf("line count %d exceeds %d lines", lines, opts.maxLines)}
+		}
+	}
+
+	// unchanged files keep the keywords of the previous run
+	key := ""
+	if opts.cache != nil {
+		key = keywordCacheKey(filePath, code, opts)
+		if keywords, ok := opts.cache.get(key); ok {
+			return keywords, nil
+		}
+	}
+
+	if opts.parsed != nil {
+		opts.parsed(filePath)
+	}
+
 	parser := sitter.NewParser()
 	defer parser.Close()
 
-	parser.SetLanguage(language)
+	if err := parser.SetLanguage(language); err != nil {
+		return nil, &skipError{reason: fmt.Sprintf("grammar incompatible: %s", err)}
+	}
+
+	// large files are parsed piecewise to bound the size of the syntax tree
+	if opts.chunkSize > 0 && len(code) > opts.chunkSize {
+		chunks := chunkSource(code, opts.chunkSize, chunkOverlap)
+		log.Trace().Str("path", filePath).Int("chunks", len(chunks)).Msg("Parsing in chunks")
+		keywords, err := parseChunks(parser, filePath, chunks, opts.comments)
+		if err == nil && opts.cache != nil {
+			opts.cache.put(key, keywords)
+		}
+		return keywords, err
+	}
 
 	// Parse the file with optional old tree for incremental parsing
 	tree := parser.Parse(code, nil)
+	if tree == nil {
+		return nil, &skipError{reason: "parser returned no tree"}
+	}
+	defer tree.Close()
 	log.Trace().Str("path", filePath).Msg("Parsed")
 
 	root := tree.RootNode()
@@ -395,29 +1171,24 @@ func parseFile(filePath string) ([]string, error) {
 	// }
 
 	// Build the code map
-	codeMap, err := mapper.GetCodeMap(root, filePath, code)
+	codeMap, err := mapper.GetCodeMap(root, filePath, code, opts.comments)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build code map: %w", err)
 	}
 
-	return codeMap, nil
-}
-
-func matchesIgnoreList(path string, ignoreList []string) bool {
-	for _, pattern := range ignoreList {
-		if matched, _ := filepath.Match(pattern, filepath.Base(path)); matched {
-			return true
-		}
-		if strings.HasPrefix(path, pattern) {
-			return true
-		}
+	if opts.cache != nil {
+		opts.cache.put(key, codeMap)
 	}
-	return false
+
+	return codeMap, nil
 }
 
-func getContextFileTree(dirPath string, ignoreList []string) (map[string]ctxtypes.FileSystemNode, error) {
+// getContextFileTree walks the tree at dirPath, loading the ignore files of
+// each directory it enters into ignores, and parses the files that aren't ignored
+func getContextFileTree(dirPath string, ignores *ctxignore.IgnoreSet, opts parseOptions) (map[string]ctxtypes.FileSystemNode, error) {
 	// Initialize the root node as a directory with an empty map for its children
 	root := &ctxtypes.FileSystemNode{Directory: true, Children: make(map[string]*ctxtypes.FileSystemNode)}
+	files := []fileJob{}
 
 	// Walk through the directory tree
 	err := filepath.Walk(dirPath, func(path string, info fs.FileInfo, err error) error {
@@ -449,17 +1220,23 @@ func getContextFileTree(dirPath string, ignoreList []string) (map[string]ctxtype
 			}
 		}
 
-		// Extract the name of the current file or directory
-		// name := parts[len(parts)-1]
+		// nodes are keyed by their base name, like the parent directories above
+		name := parts[len(parts)-1]
 
 		// Check if the path matches the ignore list
-		if matchesIgnoreList(path, ignoreList) {
+		if ignored, reason := ignores.Matches(relPath, info.IsDir()); ignored {
+			log.Debug().Str("path", relPath).Str("rule", reason).Msg("Ignored")
 			n := ctxtypes.FileSystemNode{Skip: true}
 			if info.IsDir() {
 				n.Directory = true
 			}
 			// Mark the node as ignored
-			node.Children[relPath] = &n
+			node.Children[name] = &n
+			if opts.stream != nil {
+				if err := opts.stream(filepath.ToSlash(relPath), n); err != nil {
+					return err
+				}
+			}
 			if info.IsDir() {
 				return filepath.SkipDir // Skip ignored directories
 			}
@@ -468,24 +1245,29 @@ func getContextFileTree(dirPath string, ignoreList []string) (map[string]ctxtype
 
 		// Add the node to the tree
 		if info.IsDir() {
+			// its ignore files apply to the entries below it
+			if err := ignores.LoadDir(filepath.ToSlash(relPath)); err != nil {
+				return err
+			}
 			// If the current item is a directory, create a node with an empty children map
-			node.Children[relPath] = &ctxtypes.FileSystemNode{
+			node.Children[name] = &ctxtypes.FileSystemNode{
 				Directory: true,
 				Children:  make(map[string]*ctxtypes.FileSystemNode),
 			}
 		} else {
-			// Parse the file for keywords
-			if keywords, err := parseFile(relPath); err != nil {
-				node.Children[relPath] = &ctxtypes.FileSystemNode{}
-			} else {
-				// If the current item is a file, create a node without children
-				node.Children[relPath] = &ctxtypes.FileSystemNode{Keywords: keywords}
-			}
+			// files are parsed once the walk is done, see parseFiles
+			node.Children[name] = &ctxtypes.FileSystemNode{}
+			files = append(files, fileJob{path: relPath, node: node.Children[name]})
+			return nil
 		}
 
 		// Log the addition to the tree
 		log.Debug().Str("path", path).Msg("Added to tree")
 
+		if opts.stream != nil {
+			return opts.stream(filepath.ToSlash(relPath), *node.Children[name])
+		}
+
 		return nil
 	})
 
@@ -493,8 +1275,23 @@ func getContextFileTree(dirPath string, ignoreList []string) (map[string]ctxtype
 		return nil, fmt.Errorf("failed to walk directory (%s): %w", dirPath, err)
 	}
 
+	if !opts.noCache && !opts.noKeywords {
+		opts.cache = loadKeywordCache(filepath.Join(dirPath, keywordCacheFile))
+	}
+
+	if err := parseFiles(files, opts); err != nil {
+		return nil, fmt.Errorf("failed to walk directory (%s): %w", dirPath, err)
+	}
+
+	if opts.cache != nil {
+		if err := opts.cache.save(); err != nil {
+			log.Warn().Err(err).Msg("Failed to save keyword cache")
+		}
+	}
+
 	// Wrap the root node in a map with the root directory path as the key
-	rootNode := map[string]ctxtypes.FileSystemNode{dirPath: *root}
+	// paths are relative to the root, its absolute path is kept in ApplicationContext.Root
+	rootNode := map[string]ctxtypes.FileSystemNode{ctxtypes.RootKey: *root}
 
 	return rootNode, nil
 }
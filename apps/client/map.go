@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	ctxexcludes "github.com/cyber-nic/ctx/libs/excludes"
+	ctxignore "github.com/cyber-nic/ctx/libs/ignore"
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	ctxutils "github.com/cyber-nic/ctx/libs/utils"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	contextFormatTree = "tree"
+	contextFormatFlat = "flat"
+)
+
+// buildAppContext walks root and returns the application context in the requested encoding.
+// Directories with more than summarizeOver entries are summarized, see ctxtypes.SummarizeDirectories,
+// and the files they hold are returned by directory path.
+func buildAppContext(root string, ignoreOpts ctxignore.Options, opts parseOptions, format string, summarizeOver int) (ctxtypes.ApplicationContext, map[string][]string, error) {
+	appCtx := ctxtypes.ApplicationContext{
+		Root: root,
+		FileSystemDetails: []string{
+			"'root' is the absolute path of the repository, every path in the context is relative to it. The root directory is keyed '.' in 'fs'",
+			"'Skip' signifies that the file or directory exists, but content is ignored",
+			"'SkipReason' explains why a skipped file's content was ignored, e.g. it exceeded a size limit",
+			"'keywords' include the web routes a file declares, e.g. 'GET /users/:id'",
+			"'role' classifies a file as source, test, config, docs or build code, or as a lockfile whose 'keywords' are its pinned dependencies as name@version",
+			"'pinned' lists files whose full content is always provided in 'file_contents'",
+			"'references' lists reference documents provided in 'file_contents'. They are not part of the codebase and must never be edited",
+		},
+		FileContents: map[string]string{},
+	}
+
+	if format != contextFormatTree && format != contextFormatFlat {
+		return appCtx, nil, fmt.Errorf("unknown context format: %s", format)
+	}
+
+	// Load the effective ignore set: default excludes, .gitignore and .ctxignore files, -ignore flags
+	ignores, err := newIgnoreSet(root, ignoreOpts)
+	if err != nil {
+		return appCtx, nil, err
+	}
+
+	rootNode, err := getContextFileTree(root, ignores, opts)
+	if err != nil {
+		return appCtx, nil, err
+	}
+	appCtx.FileSystem = rootNode
+
+	if opts.todos {
+		appCtx.FileSystemDetails = append(appCtx.FileSystemDetails,
+			"'todos' lists the TODO, FIXME, HACK and XXX comments of a file with their line, use them to locate unfinished work")
+	}
+
+	if opts.noKeywords {
+		appCtx.FileSystemDetails = append(appCtx.FileSystemDetails,
+			"'keywords' were not extracted, files are only known by their path and name")
+	}
+
+	summarized := ctxtypes.SummarizeDirectories(&appCtx, summarizeOver)
+	if len(summarized) > 0 {
+		appCtx.FileSystemDetails = append(appCtx.FileSystemDetails,
+			"'summary' marks a directory whose files are not listed. 'file_count' is the number of files below it and 'keywords' their most frequent keywords. Request the directory path in 'additional_context_files' to get the content of its files")
+	}
+
+	if format == contextFormatFlat {
+		appCtx.Files = ctxtypes.Flatten(appCtx)
+		appCtx.FileSystem = nil
+		appCtx.FileSystemDetails = append(appCtx.FileSystemDetails,
+			"'files' maps each file path to its keywords, in place of the nested file system tree")
+	}
+
+	return appCtx, summarized, nil
+}
+
+// runMap implements the map command, which prints the context built for the repo root
+func runMap(args []string) {
+	fs := flag.NewFlagSet("map", flag.ExitOnError)
+	var debug = fs.Bool("debug", false, "enable debug mode")
+	var format = fs.String("format", contextFormatTree, "output format: 'tree' or 'flat' ({path: [keywords]})")
+	var maxFileSize = fs.Int64("max-file-size", 1<<20, "skip keyword extraction for files larger than this many bytes, replacing the per-language defaults when set (0 disables)")
+	var maxLines = fs.Int("max-lines", 20000, "skip keyword extraction for files with more lines than this, replacing the per-language defaults when set (0 disables)")
+	var todos = fs.Bool("todos", false, "include the TODO, FIXME, HACK and XXX comments of each file")
+	var commentKeywords = fs.Bool("comment-keywords", false, "also extract the words of comments and docstrings as keywords")
+	var noKeywords = fs.Bool("no-keywords", false, "map the file structure only, skipping keyword extraction")
+	var noCache = fs.Bool("no-cache", false, "parse every file, ignoring the keywords cached in "+keywordCacheFile)
+	var parseConcurrency = fs.Int("parse-concurrency", defaultParseConcurrency, "number of files parsed at once while walking the tree")
+	var chunkSize = fs.Int("chunk-size", 256<<10, "parse files larger than this many bytes in overlapping chunks (0 disables)")
+	var summarizeOver = fs.Int("summarize-over", 0, "summarize directories with more entries than this (0 disables)")
+	var rootFlag = fs.String("root", "", "repo root the context is built from, detected from .git or go.mod when empty")
+	ignoreFlags := registerIgnoreFlags(fs)
+	fs.Parse(args)
+
+	ctxutils.ConfigLogging(debug)
+
+	root, _, err := enterRepoRoot(*rootFlag)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error locating repo root")
+	}
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	opts := parseOptions{maxFileSize: *maxFileSize, maxLines: *maxLines, chunkSize: *chunkSize, noKeywords: *noKeywords, todos: *todos, comments: *commentKeywords, concurrency: *parseConcurrency, noCache: *noCache}
+	opts.languages = resolveLimits(loadConfig(), opts, set["max-file-size"], set["max-lines"])
+
+	appCtx, _, err := buildAppContext(root, ignoreFlags.options(), opts, *format, *summarizeOver)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error getting folder structure")
+	}
+
+	if *format == contextFormatFlat {
+		out, _ := json.Marshal(appCtx.Files)
+		fmt.Println(string(out))
+		return
+	}
+
+	ctxutils.PrintStructOut(appCtx.FileSystem)
+}
+
+// runExcludes implements the excludes command, which lists the effective excludes
+// for the repo root along with where each comes from
+func runExcludes(args []string) {
+	fs := flag.NewFlagSet("excludes", flag.ExitOnError)
+	var debug = fs.Bool("debug", false, "enable debug mode")
+	var rootFlag = fs.String("root", "", "repo root the excludes are detected for, detected from .git or go.mod when empty")
+	fs.Parse(args)
+
+	ctxutils.ConfigLogging(debug)
+
+	root, _, err := enterRepoRoot(*rootFlag)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error locating repo root")
+	}
+
+	sources, err := ctxexcludes.Resolve(root)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error loading excludes")
+	}
+
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Printf("%s\t%s\n", name, sources[name])
+	}
+}
+
+// runCheckIgnore implements the check-ignore command which, like git check-ignore -v,
+// reports the rule ignoring each path. It exits with 1 when no path is ignored.
+func runCheckIgnore(args []string) {
+	fs := flag.NewFlagSet("check-ignore", flag.ExitOnError)
+	var debug = fs.Bool("debug", false, "enable debug mode")
+	var nonMatching = fs.Bool("n", false, "also list the paths that are not ignored")
+	var rootFlag = fs.String("root", "", "repo root, detected from .git or go.mod when empty")
+	ignoreFlags := registerIgnoreFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s check-ignore [flags] <path>...\n", filepath.Base(os.Args[0]))
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	ctxutils.ConfigLogging(debug)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	root, invokedFrom, err := enterRepoRoot(*rootFlag)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error locating repo root")
+	}
+
+	ignores, err := effectiveIgnore(root, ignoreFlags.options())
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error loading ignore files")
+	}
+
+	matched := false
+	for _, arg := range fs.Args() {
+		p := fromInvocation(root, invokedFrom, arg)
+
+		// paths that don't exist are checked as files
+		info, err := os.Stat(p)
+		isDir := err == nil && info.IsDir()
+
+		ignored, rule := ignores.Matches(p, isDir)
+		switch {
+		case ignored:
+			matched = true
+			fmt.Printf("%s\t%s\n", rule, arg)
+		case rule != "":
+			// re-included by a negated pattern
+			fmt.Printf("%s\t%s\n", rule, arg)
+		case *nonMatching:
+			fmt.Printf("::\t%s\n", arg)
+		}
+	}
+
+	if !matched {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,78 @@
+package mapper
+
+import (
+	"strings"
+	"unicode"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// maxCommentKeywords bounds the words collected from the comments of a file,
+// a heavily documented file would otherwise drown its identifiers
+const maxCommentKeywords = 200
+
+// stopWords are the words too common in prose to select a file by
+var stopWords = kindSet(
+	"the", "and", "for", "are", "but", "not", "you", "all", "any", "can", "has", "had", "her", "his",
+	"its", "our", "was", "one", "out", "use", "see", "get", "set", "this", "that", "with", "from",
+	"into", "onto", "than", "then", "them", "they", "there", "their", "these", "those", "when",
+	"where", "which", "while", "who", "whom", "why", "how", "what", "will", "would", "should",
+	"could", "may", "might", "must", "been", "being", "have", "does", "did", "done", "each",
+	"else", "only", "also", "just", "more", "most", "some", "such", "very", "over", "under",
+	"about", "after", "before", "because", "other", "same", "both", "either", "here", "via",
+	"per", "yet", "too", "it's", "don't", "doesn't", "isn't",
+)
+
+// isDocstring reports whether the node is a Python docstring, the string that
+// opens a module, class or function body
+func isDocstring(n *sitter.Node) bool {
+	if n.Kind() != "string" {
+		return false
+	}
+	stmt := n.Parent()
+	if stmt == nil || stmt.Kind() != "expression_statement" || stmt.NamedChildCount() != 1 {
+		return false
+	}
+	body := stmt.Parent()
+	if body == nil || (body.Kind() != "module" && body.Kind() != "block") {
+		return false
+	}
+	first := body.NamedChild(0)
+	return first != nil && first.Id() == stmt.Id()
+}
+
+// commentWords splits a comment into lowercase words, dropping the comment
+// markers, stop words and words shorter than three characters
+func commentWords(text string) []string {
+	words := []string{}
+	for _, field := range strings.Fields(text) {
+		word := strings.ToLower(strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}))
+		if len([]rune(word)) < 3 || stopWords[word] || strings.IndexFunc(word, unicode.IsLetter) < 0 {
+			continue
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// collectComments walks the tree and records the words of each comment and docstring
+func collectComments(root *sitter.Node, lang language, sourceCode []byte, add func(word string)) {
+	var walk func(n *sitter.Node)
+	walk = func(n *sitter.Node) {
+		if n == nil {
+			return
+		}
+		if lang.comments[n.Kind()] || (lang.docstrings && isDocstring(n)) {
+			for _, word := range commentWords(n.Utf8Text(sourceCode)) {
+				add(word)
+			}
+			return
+		}
+		for i := uint(0); i < n.NamedChildCount(); i++ {
+			walk(n.NamedChild(i))
+		}
+	}
+	walk(root)
+}
@@ -0,0 +1,83 @@
+package mapper
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGetCodeMapComments(t *testing.T) {
+	tests := []struct {
+		file   string
+		source string
+		// want are the comment words following the identifiers, wantNot words that aren't keywords
+		want    []string
+		wantNot []string
+	}{
+		{
+			file:   "a.go",
+			source: "package a\n\n// parse the invoice total\nfunc sum() {}\n",
+			want:   []string{"invoice", "parse", "total"},
+		},
+		{
+			file:    "a.js",
+			source:  "/**\n * Computes the VAT of an order.\n * @param {Order} order\n */\nfunction vat(order) {}\n",
+			want:    []string{"computes", "param", "order"},
+			wantNot: []string{"the", "of", "an"},
+		},
+		{
+			// docstrings count, other strings don't
+			file:    "a.py",
+			source:  "\"\"\"Invoice rendering helpers.\"\"\"\n\ndef render():\n    \"\"\"Render the invoice as PDF.\"\"\"\n    return \"not a docstring\"\n",
+			want:    []string{"helpers", "invoice", "pdf", "rendering"},
+			wantNot: []string{"docstring"},
+		},
+	}
+	for _, tt := range tests {
+		identifiers := sourceMap(t, tt.file, []byte(tt.source), false)
+		got := sourceMap(t, tt.file, []byte(tt.source), true)
+
+		if !slices.Equal(got[:len(identifiers)], identifiers) {
+			t.Errorf("%s: the identifiers %q don't come first in %q", tt.file, identifiers, got)
+			continue
+		}
+		words := got[len(identifiers):]
+		for _, w := range tt.want {
+			if !slices.Contains(words, w) && !slices.Contains(identifiers, w) {
+				t.Errorf("%s: %q isn't in %q", tt.file, w, got)
+			}
+		}
+		for _, w := range tt.wantNot {
+			if slices.Contains(got, w) {
+				t.Errorf("%s: %q is in %q", tt.file, w, got)
+			}
+		}
+		if !slices.IsSorted(words) {
+			t.Errorf("%s: comment words %q aren't sorted", tt.file, words)
+		}
+	}
+}
+
+func TestCommentWords(t *testing.T) {
+	got := commentWords("// Don't retry the 3 HTTP calls, it's v2-only!")
+	want := []string{"retry", "http", "calls", "v2-only"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestGetCodeMapCommentsCapped(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("package a\n\n")
+	for i := range 2 * maxCommentKeywords {
+		fmt.Fprintf(&b, "// word%d\n", i)
+	}
+	b.WriteString("func a() {}\n")
+
+	identifiers := sourceMap(t, "a.go", []byte(b.String()), false)
+	got := sourceMap(t, "a.go", []byte(b.String()), true)
+	if n := len(got) - len(identifiers); n != maxCommentKeywords {
+		t.Errorf("got %d comment words, want %d", n, maxCommentKeywords)
+	}
+}
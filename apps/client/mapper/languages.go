@@ -0,0 +1,189 @@
+package mapper
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Limits bound the files keywords are extracted from. Zero values disable a limit.
+type Limits struct {
+	MaxFileSize int64
+	MaxLines    int
+}
+
+// language lists the tree-sitter node kinds that matter for keyword extraction
+type language struct {
+	name string
+	// limits are the default parse limits, weighing parse cost against keyword value
+	limits Limits
+	// declarations are nodes whose name and identifiers are collected
+	declarations map[string]bool
+	// identifiers are the leaf kinds collected as keywords
+	identifiers map[string]bool
+	// typeContainers are the struct, interface and class bodies whose type references are collected
+	typeContainers map[string]bool
+	// typeReferences are the type kinds collected within type containers
+	typeReferences map[string]bool
+	// builtinTypes are type names too common to be a useful keyword
+	builtinTypes map[string]bool
+	// routes finds the web framework route declarations, collected as keywords
+	routes routeFinder
+	// comments are the comment kinds whose words are collected on request
+	comments map[string]bool
+	// docstrings collects the words of Python docstrings along with comments
+	docstrings bool
+}
+
+func kindSet(kinds ...string) map[string]bool {
+	set := make(map[string]bool, len(kinds))
+	for _, k := range kinds {
+		set[k] = true
+	}
+	return set
+}
+
+var (
+	golang = language{
+		name: "go",
+		// dense with declarations, large files are still worth parsing
+		limits:       Limits{MaxFileSize: 2 << 20, MaxLines: 50000},
+		declarations: kindSet("function_declaration", "method_declaration", "type_spec", "type_alias"),
+		identifiers:  kindSet("identifier", "field_identifier", "package_identifier"),
+		// e.g. `db *sql.DB` yields sql.DB and DB
+		typeContainers: kindSet("struct_type", "interface_type"),
+		typeReferences: kindSet("type_identifier", "qualified_type"),
+		builtinTypes: kindSet("any", "bool", "byte", "complex64", "complex128", "error", "float32", "float64",
+			"int", "int8", "int16", "int32", "int64", "rune", "string",
+			"uint", "uint8", "uint16", "uint32", "uint64", "uintptr"),
+		routes:   routeCall(goRouteMethods, "selector_expression", "field"),
+		comments: kindSet("comment"),
+	}
+	java = language{
+		name: "java",
+		// verbose, a large class holds fewer declarations per line than Go
+		limits: Limits{MaxFileSize: 1 << 20, MaxLines: 20000},
+		declarations: kindSet("class_declaration", "interface_declaration", "enum_declaration", "record_declaration",
+			"annotation_type_declaration", "method_declaration", "constructor_declaration", "field_declaration"),
+		// type names are identifiers too, e.g. the types of fields and parameters
+		identifiers: kindSet("identifier", "type_identifier"),
+		comments:    kindSet("line_comment", "block_comment"),
+	}
+	// c covers headers too, whose prototypes and macros are collected as identifiers
+	c = language{
+		name:   "c",
+		limits: Limits{MaxFileSize: 1 << 20, MaxLines: 20000},
+		declarations: kindSet("function_definition", "struct_specifier", "union_specifier", "enum_specifier",
+			"type_definition", "preproc_function_def"),
+		identifiers: kindSet("identifier", "field_identifier", "type_identifier"),
+		comments:    kindSet("comment"),
+	}
+	cpp = language{
+		name:   "cpp",
+		limits: Limits{MaxFileSize: 1 << 20, MaxLines: 20000},
+		declarations: kindSet("function_definition", "struct_specifier", "class_specifier", "union_specifier",
+			"enum_specifier", "namespace_definition", "type_definition", "alias_declaration", "preproc_function_def"),
+		identifiers: kindSet("identifier", "field_identifier", "type_identifier", "namespace_identifier"),
+		comments:    kindSet("comment"),
+	}
+	python = language{
+		name:         "python",
+		limits:       Limits{MaxFileSize: 1 << 20, MaxLines: 20000},
+		declarations: kindSet("function_definition", "class_definition"),
+		identifiers:  kindSet("identifier"),
+		routes:       pythonRoute,
+		comments:     kindSet("comment"),
+		docstrings:   true,
+	}
+	javascript = language{
+		name: "javascript",
+		// large files are mostly bundled or minified output
+		limits:       Limits{MaxFileSize: 512 << 10, MaxLines: 10000},
+		declarations: kindSet("function_declaration", "generator_function_declaration", "class_declaration", "method_definition"),
+		identifiers:  kindSet("identifier", "property_identifier"),
+		routes:       routeCall(jsRouteMethods, "member_expression", "property"),
+		comments:     kindSet("comment"),
+	}
+	typescript = language{
+		name:   "typescript",
+		limits: Limits{MaxFileSize: 1 << 20, MaxLines: 20000},
+		declarations: kindSet("function_declaration", "generator_function_declaration", "class_declaration",
+			"abstract_class_declaration", "method_definition", "method_signature", "interface_declaration",
+			"type_alias_declaration", "enum_declaration"),
+		identifiers:    kindSet("identifier", "property_identifier"),
+		typeContainers: kindSet("interface_body", "object_type", "class_body"),
+		typeReferences: kindSet("type_identifier", "nested_type_identifier"),
+		routes:         routeCall(jsRouteMethods, "member_expression", "property"),
+		comments:       kindSet("comment"),
+	}
+	// dockerfile keeps the base images and stage names, the exposed ports and the
+	// ARG and ENV names, not the commands that are run
+	dockerfile = language{
+		name:         "dockerfile",
+		limits:       Limits{MaxFileSize: 256 << 10, MaxLines: 5000},
+		declarations: kindSet("arg_instruction", "env_pair"),
+		// an image is kept whole, e.g. golang:1.23-alpine
+		identifiers: kindSet("image_spec", "image_alias", "expose_port"),
+		comments:    kindSet("comment"),
+	}
+)
+
+// languages maps file extensions to their node kinds
+var languages = map[string]language{
+	".go":   golang,
+	".java": java,
+	".c":    c,
+	".h":    c,
+	".cc":   cpp,
+	".cpp":  cpp,
+	".cxx":  cpp,
+	".hpp":  cpp,
+	".py":   python,
+	".js":   javascript,
+	".jsx":  javascript,
+	".ts":   typescript,
+	".tsx":  typescript,
+}
+
+// IsDockerfile reports whether the file is a Dockerfile, e.g. Dockerfile,
+// Dockerfile.prod, app.Dockerfile or Containerfile. Source files such as
+// dockerfile.go aren't.
+func IsDockerfile(filename string) bool {
+	name := strings.ToLower(filepath.Base(filename))
+	if _, ok := languages[filepath.Ext(name)]; ok {
+		return false
+	}
+	for _, base := range []string{"dockerfile", "containerfile"} {
+		if name == base || strings.HasPrefix(name, base+".") || strings.HasSuffix(name, "."+base) {
+			return true
+		}
+	}
+	return false
+}
+
+// languageFor returns the node kinds for the file, defaulting to Go's
+func languageFor(filename string) language {
+	if IsDockerfile(filename) {
+		return dockerfile
+	}
+	if l, ok := languages[strings.ToLower(filepath.Ext(filename))]; ok {
+		return l
+	}
+	return golang
+}
+
+// LanguageName returns the name of the file's language, empty when the extension isn't mapped
+func LanguageName(filename string) string {
+	if IsDockerfile(filename) {
+		return dockerfile.name
+	}
+	return languages[strings.ToLower(filepath.Ext(filename))].name
+}
+
+// DefaultLimits returns the default parse limits of each language, by name
+func DefaultLimits() map[string]Limits {
+	limits := map[string]Limits{dockerfile.name: dockerfile.limits}
+	for _, l := range languages {
+		limits[l.name] = l.limits
+	}
+	return limits
+}
@@ -0,0 +1,88 @@
+package mapper
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLanguageKindsExist(t *testing.T) {
+	files := []string{"Dockerfile"}
+	for ext := range grammars {
+		files = append(files, "file"+ext)
+	}
+	for _, file := range files {
+		grammar, lang := grammarFor(file), languageFor(file)
+		sets := map[string]map[string]bool{
+			"declarations":   lang.declarations,
+			"identifiers":    lang.identifiers,
+			"typeContainers": lang.typeContainers,
+			"typeReferences": lang.typeReferences,
+			"comments":       lang.comments,
+		}
+		for name, kinds := range sets {
+			for kind := range kinds {
+				if grammar.IdForNodeKind(kind, true) == 0 {
+					t.Errorf("%s: %s kind %q isn't a node of the grammar", lang.name, name, kind)
+				}
+			}
+		}
+	}
+}
+
+func TestGetCodeMapDeclarations(t *testing.T) {
+	tests := []struct {
+		file   string
+		source string
+		// want are the declared names, each must be a keyword
+		want []string
+	}{
+		{"a.go", "package a\n\ntype Store struct{}\n\ntype ID = int\n\nfunc (s Store) Find() {}\n\nfunc NewStore() Store { return Store{} }\n",
+			[]string{"Store", "ID", "Find", "NewStore"}},
+		{"a.java", "class Store {\n  Store() {}\n  void find() {}\n}\ninterface Finder {}\nenum Role { ADMIN }\nrecord User(int id) {}\n",
+			[]string{"Store", "find", "Finder", "Role", "User"}},
+		{"a.c", "struct user { int id; };\ntypedef struct user user_t;\nenum role { ADMIN };\n#define MAX(a, b) a\nint find(void) { return 0; }\n",
+			[]string{"user", "user_t", "role", "MAX", "find"}},
+		{"a.cpp", "namespace store {\nclass Store { void find(); };\nusing Id = int;\nint lookup() { return 0; }\n}\n",
+			[]string{"store", "Store", "find", "Id", "lookup"}},
+		{"a.js", "class Store {\n  find() {}\n}\nfunction load() {}\nfunction* ids() {}\n",
+			[]string{"Store", "find", "load", "ids"}},
+		{"a.py", "class Store:\n    def find(self):\n        pass\n\ndef load():\n    pass\n",
+			[]string{"Store", "find", "load"}},
+		{"a.ts", "interface Finder { find(): void }\ntype Id = number\nenum Role { Admin }\nabstract class Base {}\nclass Store { load() {} }\nfunction open() {}\n",
+			[]string{"Finder", "find", "Id", "Role", "Base", "Store", "load", "open"}},
+	}
+	for _, tt := range tests {
+		got := sourceMap(t, tt.file, []byte(tt.source), false)
+		for _, name := range tt.want {
+			if !slices.Contains(got, name) {
+				t.Errorf("%s: %q isn't in %q", tt.file, name, got)
+			}
+		}
+	}
+}
+
+func TestIsDockerfile(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"Dockerfile", true},
+		{"deploy/Dockerfile.prod", true},
+		{"app.Dockerfile", true},
+		{"dockerfile", true},
+		{"Containerfile", true},
+		{"build/api.containerfile", true},
+		{"Dockerfiles/README.md", false},
+		{"dockerfile.go", false},
+		{"MyDockerfile", false},
+		{"docker-compose.yml", false},
+	}
+	for _, tt := range tests {
+		if got := IsDockerfile(tt.path); got != tt.want {
+			t.Errorf("IsDockerfile(%q) = %t, want %t", tt.path, got, tt.want)
+		}
+		if name := LanguageName(tt.path); tt.want && name != "dockerfile" {
+			t.Errorf("%s is mapped as %q", tt.path, name)
+		}
+	}
+}
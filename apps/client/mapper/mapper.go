@@ -3,6 +3,7 @@ package mapper
 import (
 	"fmt"
 	"regexp"
+	"sort"
 
 	sitter "github.com/tree-sitter/go-tree-sitter"
 )
@@ -10,47 +11,52 @@ import (
 var whitespaceRegex = regexp.MustCompile(`\s`)
 var manyWhitespaceRegex = regexp.MustCompile(`\s+`)
 
-func GetCodeMap(root *sitter.Node, filename string, sourceCode []byte) ([]string, error) {
+// GetCodeMap returns the keywords of the file's syntax tree. With comments, the
+// words of its comments and docstrings follow the identifiers.
+func GetCodeMap(root *sitter.Node, filename string, sourceCode []byte, comments bool) ([]string, error) {
 	if root == nil {
 		return nil, fmt.Errorf("root node cannot be nil")
 	}
 
 	terms := map[string]bool{}
+	lang := languageFor(filename)
 
 	// var builder strings.Builder
 	// builder.WriteString(fmt.Sprintf("## %s\n", filename))
 
-	// Helper function to recursively collect all identifier values
-	collectIdentifiers := func(node *sitter.Node) []string {
-		var values []string
-		var collect func(*sitter.Node)
+	// addTerm records the text of a node as a keyword
+	addTerm := func(n *sitter.Node) {
+		text := string(sourceCode[n.StartByte():n.EndByte()])
+		if len(text) > 1 && !whitespaceRegex.MatchString(text) {
+			terms[text] = true
+		}
+	}
 
-		collect = func(n *sitter.Node) {
-			if n == nil {
-				return
-			}
+	// Helper function to recursively collect all identifier values, and the
+	// type references of fields and members within struct, interface and class bodies
+	var collectIdentifiers func(n *sitter.Node, inTypes bool)
 
-			if n.IsNamed() {
-				nodeType := n.Kind()
-				switch nodeType {
-				case "identifier", "field_identifier", "package_identifier":
-					text := string(sourceCode[n.StartByte():n.EndByte()])
-					if len(text) > 1 && !whitespaceRegex.MatchString(text) {
-						values = append(values, text)
-					}
-				}
-			}
+	collectIdentifiers = func(n *sitter.Node, inTypes bool) {
+		if n == nil {
+			return
+		}
 
-			// Recursively process all children
-			for i := uint(0); i < n.NamedChildCount(); i++ {
-				if child := n.NamedChild(i); child != nil {
-					collect(child)
-				}
+		if n.IsNamed() {
+			kind := n.Kind()
+			if lang.identifiers[kind] {
+				addTerm(n)
+			} else if inTypes && lang.typeReferences[kind] && !lang.builtinTypes[n.Utf8Text(sourceCode)] {
+				addTerm(n)
 			}
+			inTypes = inTypes || lang.typeContainers[kind]
 		}
 
-		collect(node)
-		return values
+		// Recursively process all children
+		for i := uint(0); i < n.NamedChildCount(); i++ {
+			if child := n.NamedChild(i); child != nil {
+				collectIdentifiers(child, inTypes)
+			}
+		}
 	}
 
 	var traverse func(node *sitter.Node)
@@ -63,24 +69,18 @@ func GetCodeMap(root *sitter.Node, filename string, sourceCode []byte) ([]string
 		if node.IsNamed() {
 			nodeType := node.Kind()
 
-			switch nodeType {
-			// case "identifier", "field_identifier", "package_identifier":
-			// 	text := string(sourceCode[node.StartByte():node.EndByte()])
-			// 	if len(text) > 1 && !whitespaceRegex.MatchString(text) {
-			// 		builder.WriteString(fmt.Sprintf("%s%s\n", indent, text))
-			// 	}
-			// 	return
-
-			case "function_declaration", "method_declaration", "struct_declaration",
-				"interface_declaration", "type_declaration", "identifier", "field_identifier", "package_identifier":
-				text := string(sourceCode[node.StartByte():node.EndByte()])
-				if len(text) > 1 {
-					for _, id := range collectIdentifiers(node) {
-						terms[id] = true
-					}
-
+			switch {
+			case lang.declarations[nodeType]:
+				// the declared name may be of a kind not collected elsewhere, e.g. type_identifier
+				if name := node.ChildByFieldName("name"); name != nil {
+					addTerm(name)
 				}
+				collectIdentifiers(node, false)
 				return // Skip further traversal for this branch
+
+			case lang.identifiers[nodeType]:
+				addTerm(node)
+				return
 			}
 		}
 
@@ -94,10 +94,29 @@ func GetCodeMap(root *sitter.Node, filename string, sourceCode []byte) ([]string
 
 	traverse(root)
 
-	keywords := []string{}
+	// routes are kept whole, e.g. "GET /users/:id", to match prompts about an endpoint
+	collectRoutes(root, lang.routes, sourceCode, func(route string) {
+		terms[route] = true
+	})
+
+	// sorted, so that the context of an unchanged file is identical across runs
+	keywords := make([]string, 0, len(terms))
 	for t := range terms {
 		keywords = append(keywords, t)
 	}
+	sort.Strings(keywords)
+
+	if comments {
+		words := []string{}
+		collectComments(root, lang, sourceCode, func(word string) {
+			if !terms[word] && len(words) < maxCommentKeywords {
+				terms[word] = true
+				words = append(words, word)
+			}
+		})
+		sort.Strings(words)
+		keywords = append(keywords, words...)
+	}
 
 	return keywords, nil
 }
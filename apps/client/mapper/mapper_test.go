@@ -0,0 +1,296 @@
+package mapper
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	tree_sitter_c "github.com/tree-sitter/tree-sitter-c/bindings/go"
+	tree_sitter_cpp "github.com/tree-sitter/tree-sitter-cpp/bindings/go"
+	tree_sitter_go "github.com/tree-sitter/tree-sitter-go/bindings/go"
+	tree_sitter_java "github.com/tree-sitter/tree-sitter-java/bindings/go"
+	tree_sitter_javascript "github.com/tree-sitter/tree-sitter-javascript/bindings/go"
+	tree_sitter_python "github.com/tree-sitter/tree-sitter-python/bindings/go"
+	tree_sitter_typescript "github.com/tree-sitter/tree-sitter-typescript/bindings/go"
+
+	tree_sitter_dockerfile "github.com/cyber-nic/ctx/apps/client/grammars/dockerfile"
+)
+
+// grammars are the tree-sitter languages of the fixtures, by extension
+var grammars = map[string]*sitter.Language{
+	".go":   sitter.NewLanguage(tree_sitter_go.Language()),
+	".java": sitter.NewLanguage(tree_sitter_java.Language()),
+	".c":    sitter.NewLanguage(tree_sitter_c.Language()),
+	".h":    sitter.NewLanguage(tree_sitter_c.Language()),
+	".cpp":  sitter.NewLanguage(tree_sitter_cpp.Language()),
+	".hpp":  sitter.NewLanguage(tree_sitter_cpp.Language()),
+	".js":   sitter.NewLanguage(tree_sitter_javascript.Language()),
+	".py":   sitter.NewLanguage(tree_sitter_python.Language()),
+	".ts":   sitter.NewLanguage(tree_sitter_typescript.LanguageTypescript()),
+}
+
+// dockerfileGrammar is the grammar of the Dockerfile fixtures, matched by name
+var dockerfileGrammar = sitter.NewLanguage(tree_sitter_dockerfile.Language())
+
+// grammarFor returns the tree-sitter language of the fixture
+func grammarFor(file string) *sitter.Language {
+	if IsDockerfile(file) {
+		return dockerfileGrammar
+	}
+	return grammars[filepath.Ext(file)]
+}
+
+// codeMap parses the fixture and returns its keywords
+func codeMap(t *testing.T, file string, comments bool) []string {
+	t.Helper()
+	source, err := os.ReadFile(filepath.Join("testdata", file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sourceMap(t, file, source, comments)
+}
+
+// sourceMap parses the source as the named file and returns its keywords
+func sourceMap(t *testing.T, file string, source []byte, comments bool) []string {
+	t.Helper()
+	parser := sitter.NewParser()
+	defer parser.Close()
+	if err := parser.SetLanguage(grammarFor(file)); err != nil {
+		t.Fatal(err)
+	}
+	tree := parser.Parse(source, nil)
+	defer tree.Close()
+
+	keywords, err := GetCodeMap(tree.RootNode(), file, source, comments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keywords
+}
+
+func TestGetCodeMap(t *testing.T) {
+	tests := []struct {
+		file     string
+		comments bool
+		want     []string
+	}{
+		{
+			// struct fields yield their type references, builtins aside
+			file: "store.go",
+			want: []string{"DB", "Find", "Finder", "GET", "GET /users/:id", "Name", "NewStore", "QueryRow", "Scan", "Store", "User",
+				"cache", "db", "gin", "handleFind", "id", "limit", "routes", "row", "sql", "sql.DB", "store"},
+		},
+		{
+			// comment words follow the identifiers, skipping those already collected
+			file:     "store.go",
+			comments: true,
+			want: []string{"DB", "Find", "Finder", "GET", "GET /users/:id", "Name", "NewStore", "QueryRow", "Scan", "Store", "User",
+				"cache", "db", "gin", "handleFind", "id", "limit", "routes", "row", "sql", "sql.DB", "store",
+				"database", "persists", "users"},
+		},
+		{
+			file: "store.java",
+			want: []string{"ADMIN", "Finder", "MEMBER", "Map", "Override", "Role", "String", "User", "UserStore",
+				"com", "example", "find", "get", "id", "java", "store", "users", "util"},
+		},
+		{
+			// macros and the tags of structs and enums are collected, not the includes
+			file: "store.c",
+			want: []string{"ADMIN", "MAX", "MEMBER", "count", "id", "name", "role", "user", "user_find", "user_t", "users"},
+		},
+		{
+			file: "store.cpp",
+			want: []string{"User", "UserId", "UserStore", "find", "id", "name", "std", "store", "string", "users_", "vector"},
+		},
+		{
+			// method names are property identifiers
+			file: "store.js",
+			want: []string{"GET /users/:id", "Map", "UserStore", "app", "constructor", "express", "find", "get", "id", "ids",
+				"json", "keys", "params", "req", "res", "store", "users"},
+		},
+		{
+			// declarations are function_definition and class_definition
+			file: "store.py",
+			want: []string{"FastAPI", "GET /users/{user_id}", "UserStore", "__init__", "app", "fastapi", "find", "get", "int",
+				"read_user", "self", "store", "user_id", "users"},
+		},
+		{
+			// docstrings are read along with comments
+			file:     "store.py",
+			comments: true,
+			want: []string{"FastAPI", "GET /users/{user_id}", "UserStore", "__init__", "app", "fastapi", "find", "get", "int",
+				"read_user", "self", "store", "user_id", "users",
+				"look", "memory", "persists", "user"},
+		},
+		{
+			// base images and stage names, ports, then ARG and ENV names, without the commands
+			file: "Dockerfile",
+			want: []string{"8080", "9090/udp", "CGO_ENABLED", "GOFLAGS", "GO_VERSION", "PORT", "TARGETOS",
+				"builder", "gcr.io/distroless/static:nonroot", "golang:${GO_VERSION}-alpine", "tester"},
+		},
+		{
+			// the parser directive is a comment, kept whole like other words
+			file:     "Dockerfile",
+			comments: true,
+			want: []string{"8080", "9090/udp", "CGO_ENABLED", "GOFLAGS", "GO_VERSION", "PORT", "TARGETOS",
+				"builder", "gcr.io/distroless/static:nonroot", "golang:${GO_VERSION}-alpine", "tester",
+				"image", "runtime", "syntax=docker/dockerfile:1"},
+		},
+		{
+			// type names are type identifiers, collected from declarations and interface bodies
+			file: "store.ts",
+			want: []string{"Active", "Disabled", "Map", "POST /users", "Role", "Router", "Status", "User", "UserStore",
+				"find", "get", "id", "name", "post", "req", "res", "role", "router", "sendStatus", "users"},
+		},
+	}
+	for _, tt := range tests {
+		got := codeMap(t, tt.file, tt.comments)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s (comments %t):\n got %q\nwant %q", tt.file, tt.comments, got, tt.want)
+		}
+	}
+}
+
+func TestGetCodeMapNilRoot(t *testing.T) {
+	if _, err := GetCodeMap(nil, "a.go", nil, false); err == nil {
+		t.Error("got no error for a nil root")
+	}
+}
+
+func TestGetCodeMapFieldTypes(t *testing.T) {
+	tests := []struct {
+		file   string
+		source string
+		want   []string
+		// wantNot are builtin types and types referenced outside type bodies
+		wantNot []string
+	}{
+		{
+			file: "a.go",
+			source: `package a
+
+type Store struct {
+	db    *sql.DB
+	cache map[string]*Entry
+	log   zerolog.Logger
+	count int
+	name  string
+}
+
+type Finder interface {
+	Find(id ID) (*User, error)
+}
+
+func open(cfg Config) {}
+`,
+			want:    []string{"sql.DB", "DB", "Entry", "zerolog.Logger", "Logger", "ID", "User"},
+			wantNot: []string{"int", "string", "error", "Config"},
+		},
+		{
+			file: "a.ts",
+			source: `interface Store {
+  db: Pool;
+  users: Map<string, User>;
+  count: number;
+}
+
+class Service {
+  repo: Repo.Users;
+}
+
+function open(cfg: Config) {}
+`,
+			want:    []string{"Pool", "Map", "User", "Repo.Users"},
+			wantNot: []string{"Config"},
+		},
+	}
+	for _, tt := range tests {
+		got := sourceMap(t, tt.file, []byte(tt.source), false)
+		for _, name := range tt.want {
+			if !slices.Contains(got, name) {
+				t.Errorf("%s: %q isn't in %q", tt.file, name, got)
+			}
+		}
+		for _, name := range tt.wantNot {
+			if slices.Contains(got, name) {
+				t.Errorf("%s: %q is in %q", tt.file, name, got)
+			}
+		}
+	}
+}
+
+func TestGetCodeMapHeaders(t *testing.T) {
+	tests := []struct {
+		file   string
+		source string
+		want   []string
+	}{
+		{
+			file: "user.h",
+			source: `#ifndef USER_H
+#define USER_H
+#define MAX_USERS 64
+typedef struct user { int id; } user_t;
+user_t *user_find(int id);
+int user_count(void);
+#endif
+`,
+			// prototypes are collected like definitions, they are how headers declare functions
+			want: []string{"MAX_USERS", "USER_H", "id", "user", "user_count", "user_find", "user_t"},
+		},
+		{
+			file: "user.hpp",
+			source: `#pragma once
+namespace store {
+class UserStore {
+public:
+  User *find(UserId id) const;
+private:
+  std::vector<User> users_;
+};
+}
+`,
+			want: []string{"store", "UserStore", "find", "User", "UserId", "users_", "vector"},
+		},
+	}
+	for _, tt := range tests {
+		got := sourceMap(t, tt.file, []byte(tt.source), false)
+		for _, name := range tt.want {
+			if !slices.Contains(got, name) {
+				t.Errorf("%s: %q isn't in %q", tt.file, name, got)
+			}
+		}
+	}
+}
+
+func TestGetCodeMapNoDuplicates(t *testing.T) {
+	for _, file := range []string{"Dockerfile", "store.c", "store.cpp", "store.go", "store.java", "store.js", "store.py", "store.ts"} {
+		for _, comments := range []bool{false, true} {
+			got := codeMap(t, file, comments)
+			seen := map[string]bool{}
+			for _, k := range got {
+				if seen[k] {
+					t.Errorf("%s (comments %t): %q is duplicated", file, comments, k)
+				}
+				seen[k] = true
+			}
+		}
+	}
+}
+
+func TestGetCodeMapStableOrder(t *testing.T) {
+	for _, file := range []string{"Dockerfile", "store.c", "store.cpp", "store.go", "store.java", "store.js", "store.py", "store.ts"} {
+		first := codeMap(t, file, false)
+		if !slices.IsSorted(first) {
+			t.Errorf("%s: keywords %q aren't sorted", file, first)
+		}
+		// map iteration order changes from one call to the next
+		for range 20 {
+			if got := codeMap(t, file, false); !slices.Equal(got, first) {
+				t.Fatalf("%s: got %q, then %q", file, first, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package mapper
+
+import (
+	"regexp"
+	"strings"
+
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// routeFinder returns the route declared by the node, e.g. "GET /users/:id"
+type routeFinder func(n *sitter.Node, sourceCode []byte) (string, bool)
+
+// routeMethods maps the router functions of a language to the HTTP method they
+// register, empty when the method is not implied, e.g. http.HandleFunc or app.use
+type routeMethods map[string]string
+
+var (
+	// net/http, Gin, Echo and Chi
+	goRouteMethods = routeMethods{
+		"Handle": "", "HandleFunc": "", "Any": "", "Group": "", "Route": "", "Mount": "",
+		"GET": "GET", "POST": "POST", "PUT": "PUT", "PATCH": "PATCH", "DELETE": "DELETE", "HEAD": "HEAD", "OPTIONS": "OPTIONS",
+		"Get": "GET", "Post": "POST", "Put": "PUT", "Patch": "PATCH", "Delete": "DELETE", "Head": "HEAD", "Options": "OPTIONS",
+	}
+	// Express and its look-alikes
+	jsRouteMethods = routeMethods{
+		"all": "", "use": "", "route": "",
+		"get": "GET", "post": "POST", "put": "PUT", "patch": "PATCH", "delete": "DELETE", "head": "HEAD", "options": "OPTIONS",
+	}
+	// FastAPI and Flask decorators
+	pythonRouteMethods = routeMethods{
+		"route": "", "api_route": "", "websocket": "",
+		"get": "GET", "post": "POST", "put": "PUT", "patch": "PATCH", "delete": "DELETE", "head": "HEAD", "options": "OPTIONS",
+	}
+)
+
+// methodPattern matches the method prefix of Go 1.22 patterns, e.g. "GET /users/{id}"
+var methodPattern = regexp.MustCompile(`^[A-Z]+ /`)
+
+// routeCall returns the route registered by a call to a router method, given
+// the kinds of the call's function and of the function's method name
+func routeCall(methods routeMethods, selectorKind, fieldName string) routeFinder {
+	return func(n *sitter.Node, sourceCode []byte) (string, bool) {
+		if n.Kind() != "call_expression" {
+			return "", false
+		}
+		fn := n.ChildByFieldName("function")
+		if fn == nil || fn.Kind() != selectorKind {
+			return "", false
+		}
+		return routeFromCall(methods, fn.ChildByFieldName(fieldName), n.ChildByFieldName("arguments"), sourceCode)
+	}
+}
+
+// pythonRoute returns the route registered by a decorator, e.g. @app.get("/users/{id}")
+func pythonRoute(n *sitter.Node, sourceCode []byte) (string, bool) {
+	if n.Kind() != "decorator" || n.NamedChildCount() == 0 {
+		return "", false
+	}
+	call := n.NamedChild(0)
+	if call == nil || call.Kind() != "call" {
+		return "", false
+	}
+	fn := call.ChildByFieldName("function")
+	if fn == nil || fn.Kind() != "attribute" {
+		return "", false
+	}
+	return routeFromCall(pythonRouteMethods, fn.ChildByFieldName("attribute"), call.ChildByFieldName("arguments"), sourceCode)
+}
+
+// routeFromCall builds the route from the router method name and the path, the first argument
+func routeFromCall(methods routeMethods, name, args *sitter.Node, sourceCode []byte) (string, bool) {
+	if name == nil || args == nil || args.NamedChildCount() == 0 {
+		return "", false
+	}
+	method, ok := methods[name.Utf8Text(sourceCode)]
+	if !ok {
+		return "", false
+	}
+
+	first := args.NamedChild(0)
+	if first == nil {
+		return "", false
+	}
+	path := strings.Trim(first.Utf8Text(sourceCode), "\"'`")
+	if !strings.HasPrefix(path, "/") && !methodPattern.MatchString(path) {
+		return "", false
+	}
+
+	if method == "" || methodPattern.MatchString(path) {
+		return path, true
+	}
+	return method + " " + path, true
+}
+
+// collectRoutes walks the tree and records each route declaration found
+func collectRoutes(root *sitter.Node, find routeFinder, sourceCode []byte, add func(route string)) {
+	if find == nil {
+		return
+	}
+
+	var walk func(n *sitter.Node)
+	walk = func(n *sitter.Node) {
+		if n == nil {
+			return
+		}
+		if route, ok := find(n, sourceCode); ok {
+			add(route)
+		}
+		for i := uint(0); i < n.NamedChildCount(); i++ {
+			walk(n.NamedChild(i))
+		}
+	}
+	walk(root)
+}
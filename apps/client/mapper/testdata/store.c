@@ -0,0 +1,22 @@
+#include <stdio.h>
+
+#define MAX(a, b) ((a) > (b) ? (a) : (b))
+
+/* a user record */
+struct user {
+    int id;
+    char *name;
+};
+
+typedef struct user user_t;
+
+enum role { ADMIN, MEMBER };
+
+int user_find(struct user *users, int count, int id) {
+    for (int i = 0; i < count; i++) {
+        if (users[i].id == id) {
+            return i;
+        }
+    }
+    return -1;
+}
@@ -0,0 +1,28 @@
+#include <string>
+
+namespace store {
+
+// a user record
+class User {
+public:
+    std::string name;
+    int id;
+};
+
+using UserId = int;
+
+class UserStore {
+public:
+    User *find(UserId id);
+private:
+    std::vector<User> users_;
+};
+
+User *UserStore::find(UserId id) {
+    for (auto &u : users_) {
+        if (u.id == id) return &u;
+    }
+    return nullptr;
+}
+
+}
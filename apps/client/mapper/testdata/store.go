@@ -0,0 +1,28 @@
+package store
+
+import "database/sql"
+
+// Store persists users in the database
+type Store struct {
+	db    *sql.DB
+	cache map[string]User
+	limit int
+}
+
+type Finder interface {
+	Find(id string) (User, error)
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+func (s *Store) Find(id string) (User, error) {
+	row := s.db.QueryRow("SELECT name FROM users WHERE id = ?", id)
+	var u User
+	return u, row.Scan(&u.Name)
+}
+
+func routes(r *gin.Engine, s *Store) {
+	r.GET("/users/:id", s.handleFind)
+}
@@ -0,0 +1,23 @@
+package com.example.store;
+
+import java.util.Map;
+
+/** Persists users in memory. */
+public class UserStore implements Finder {
+    private final Map<String, User> users;
+
+    public UserStore(Map<String, User> users) {
+        this.users = users;
+    }
+
+    @Override
+    public User find(String id) {
+        return users.get(id);
+    }
+}
+
+interface Finder {
+    User find(String id);
+}
+
+enum Role { ADMIN, MEMBER }
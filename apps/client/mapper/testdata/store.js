@@ -0,0 +1,20 @@
+import express from 'express';
+
+// persists users in memory
+class UserStore {
+  constructor() {
+    this.users = new Map();
+  }
+
+  find(id) {
+    return this.users.get(id);
+  }
+}
+
+function* ids(store) {
+  yield* store.users.keys();
+}
+
+const app = express();
+const store = new UserStore();
+app.get('/users/:id', (req, res) => res.json(store.find(req.params.id)));
@@ -0,0 +1,20 @@
+from fastapi import FastAPI
+
+app = FastAPI()
+
+
+class UserStore:
+    """Persists users in memory."""
+
+    def __init__(self):
+        self.users = {}
+
+    def find(self, user_id):
+        # look the user up
+        return self.users.get(user_id)
+
+
+@app.get("/users/{user_id}")
+def read_user(user_id: int):
+    store = UserStore()
+    return store.find(user_id)
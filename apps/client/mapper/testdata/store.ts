@@ -0,0 +1,23 @@
+import { Router } from 'express';
+
+// a user record
+export interface User {
+  id: number;
+  name: string;
+  role: Role;
+}
+
+export type Role = 'admin' | 'member';
+
+export enum Status { Active, Disabled }
+
+export class UserStore {
+  private users: Map<number, User> = new Map();
+
+  find(id: number): User | undefined {
+    return this.users.get(id);
+  }
+}
+
+const router = Router();
+router.post('/users', (req, res) => res.sendStatus(201));
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+const devNull = "/dev/null"
+
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// stripLineDecoration removes the line numbers of the work target that the
+// model copied into the hunks of a patch. The decoration is only stripped
+// when every context and removed line carries it, so that code which happens
+// to look like it is left alone.
+func stripLineDecoration(patch string, format ctxtypes.LineFormat) string {
+	lines := strings.Split(patch, "\n")
+
+	inHunk, decorated := false, 0
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case strings.HasPrefix(line, "diff "):
+			inHunk = false
+		case !inHunk || strings.HasPrefix(line, "+"):
+		case strings.HasPrefix(line, " ") || strings.HasPrefix(line, "-"):
+			if _, ok := format.Strip(line[1:]); !ok {
+				return patch
+			}
+			decorated++
+		}
+	}
+	if decorated == 0 {
+		return patch
+	}
+
+	inHunk = false
+	for i, line := range lines {
+		if strings.HasPrefix(line, "@@") || strings.HasPrefix(line, "diff ") {
+			inHunk = strings.HasPrefix(line, "@@")
+			continue
+		}
+		if !inHunk || line == "" {
+			continue
+		}
+		if stripped, ok := format.Strip(line[1:]); ok {
+			lines[i] = line[:1] + stripped
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// stripResponseDecoration strips the line decoration from the patch of the
+// work response, its candidates and its suggested tests, which may update
+// existing test files sent decorated
+func stripResponseDecoration(resp *ctxtypes.StepFileWorkResponseSchema, format ctxtypes.LineFormat) {
+	resp.Data.Patch = stripLineDecoration(resp.Data.Patch, format)
+	for i := range resp.Candidates {
+		resp.Candidates[i].Patch = stripLineDecoration(resp.Candidates[i].Patch, format)
+	}
+	for i := range resp.Tests {
+		resp.Tests[i].Patch = stripLineDecoration(resp.Tests[i].Patch, format)
+	}
+}
+
+// normalizePatch rewrites the headers of a single-file patch returned by the
+// model so that it can be concatenated with others into one multi-file patch.
+// Anything before the first hunk is discarded and replaced by git-style headers.
+// A move from another path gets rename headers, so that applying the patch
+// moves the file. from is the same as path when the file was already moved.
+func normalizePatch(path, from string, op ctxtypes.FileOperation, patch string) (string, error) {
+	lines := strings.Split(strings.ReplaceAll(patch, "\r\n", "\n"), "\n")
+
+	start := -1
+	for i, line := range lines {
+		if strings.HasPrefix(line, "@@") {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return "", fmt.Errorf("patch for %s has no hunks", path)
+	}
+
+	// drop trailing blank lines, a blank context line is a single space
+	end := len(lines)
+	for end > start && lines[end-1] == "" {
+		end--
+	}
+
+	if op != ctxtypes.FileOperationMove || from == "" {
+		from = path
+	}
+	src, dst := "a/"+from, "b/"+path
+	b := strings.Builder{}
+	b.WriteString(fmt.Sprintf("diff --git a/%s b/%s\n", from, path))
+	switch op {
+	case ctxtypes.FileOperationCreate:
+		b.WriteString("new file mode 100644\n")
+		src = devNull
+	case ctxtypes.FileOperationRemove:
+		b.WriteString("deleted file mode 100644\n")
+		dst = devNull
+	case ctxtypes.FileOperationMove:
+		if from != path {
+			b.WriteString(fmt.Sprintf("rename from %s\nrename to %s\n", from, path))
+		}
+	}
+	b.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", src, dst))
+
+	for _, line := range lines[start:end] {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	return b.String(), nil
+}
+
+// combinePatches concatenates normalized single-file patches and validates the result
+func combinePatches(patches []string) (string, error) {
+	combined := strings.Join(patches, "")
+
+	if err := validatePatch(combined); err != nil {
+		return "", err
+	}
+
+	return combined, nil
+}
+
+// belowConfidence reports whether the patch is less confident than min, a
+// patch without confidence is. A min of 0 disables the check.
+func belowConfidence(data ctxtypes.PatchData, min float64) bool {
+	return min > 0 && (data.Confidence == nil || *data.Confidence < min)
+}
+
+// formatConfidence returns the confidence with two decimals, "unknown" when missing
+func formatConfidence(confidence *float64) string {
+	if confidence == nil {
+		return "unknown"
+	}
+	return fmt.Sprintf("%.2f", *confidence)
+}
+
+// confidenceNote returns the lines describing the model's assessment of the patch, none when it gave none
+func confidenceNote(data ctxtypes.PatchData) []string {
+	lines := []string{}
+	if data.Confidence != nil {
+		lines = append(lines, "confidence: "+formatConfidence(data.Confidence))
+	}
+	for _, a := range data.Assumptions {
+		lines = append(lines, "assumes: "+a)
+	}
+	return lines
+}
+
+// writeCombinedPatch combines the patches and writes the result to outFile, or stdout if empty
+func writeCombinedPatch(patches []string, outFile string) error {
+	patch, err := combinePatches(patches)
+	if err != nil {
+		return fmt.Errorf("invalid combined patch: %w", err)
+	}
+
+	if outFile == "" {
+		fmt.Print(patch)
+		return nil
+	}
+
+	return os.WriteFile(outFile, []byte(patch), 0644)
+}
+
+// validatePatch checks that every file section has headers and that each hunk's
+// line counts match its header.
+func validatePatch(patch string) error {
+	lines := strings.Split(strings.TrimSuffix(patch, "\n"), "\n")
+
+	files := 0
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			files++
+			i++
+			// skip extended headers up to the file names
+			for i < len(lines) && !strings.HasPrefix(lines[i], "--- ") {
+				if strings.HasPrefix(lines[i], "@@") || strings.HasPrefix(lines[i], "diff --git ") {
+					return fmt.Errorf("line %d: missing file headers", i+1)
+				}
+				i++
+			}
+			if i+1 >= len(lines) || !strings.HasPrefix(lines[i+1], "+++ ") {
+				return fmt.Errorf("line %d: missing '+++' header", i+1)
+			}
+			i += 2
+
+		case strings.HasPrefix(line, "@@"):
+			if files == 0 {
+				return fmt.Errorf("line %d: hunk outside of a file section", i+1)
+			}
+			m := hunkHeaderRegex.FindStringSubmatch(line)
+			if m == nil {
+				return fmt.Errorf("line %d: malformed hunk header: %s", i+1, line)
+			}
+			oldCount, newCount := hunkCount(m[2]), hunkCount(m[4])
+			i++
+
+			for oldCount > 0 || newCount > 0 {
+				if i >= len(lines) {
+					return fmt.Errorf("line %d: hunk is truncated", i+1)
+				}
+				h := lines[i]
+				switch {
+				case strings.HasPrefix(h, "\\"):
+					// "\ No newline at end of file"
+				case strings.HasPrefix(h, "+"):
+					newCount--
+				case strings.HasPrefix(h, "-"):
+					oldCount--
+				case strings.HasPrefix(h, " "), h == "":
+					oldCount--
+					newCount--
+				default:
+					return fmt.Errorf("line %d: unexpected line in hunk: %s", i+1, h)
+				}
+				if oldCount < 0 || newCount < 0 {
+					return fmt.Errorf("line %d: hunk line counts do not match header", i+1)
+				}
+				i++
+			}
+			// trailing "\ No newline at end of file"
+			if i < len(lines) && strings.HasPrefix(lines[i], "\\") {
+				i++
+			}
+
+		default:
+			return fmt.Errorf("line %d: unexpected line: %s", i+1, line)
+		}
+	}
+
+	if files == 0 {
+		return fmt.Errorf("patch is empty")
+	}
+
+	return nil
+}
+
+// hunkCount parses an optional hunk range length, which defaults to 1
+func hunkCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
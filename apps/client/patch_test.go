@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+// confidence returns a pointer to c
+func confidence(c float64) *float64 {
+	return &c
+}
+
+func TestBelowConfidence(t *testing.T) {
+	tests := []struct {
+		name       string
+		confidence *float64
+		min        float64
+		want       bool
+	}{
+		{"disabled", confidence(0.1), 0, false},
+		{"disabled without confidence", nil, 0, false},
+		{"below", confidence(0.5), 0.8, true},
+		{"equal", confidence(0.8), 0.8, false},
+		{"above", confidence(0.9), 0.8, false},
+		{"zero confidence", confidence(0), 0.1, true},
+		// a patch without confidence can't be trusted to meet the threshold
+		{"no confidence", nil, 0.8, true},
+	}
+	for _, tt := range tests {
+		if got := belowConfidence(ctxtypes.PatchData{Confidence: tt.confidence}, tt.min); got != tt.want {
+			t.Errorf("%s: got %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestConfidenceNote(t *testing.T) {
+	data := ctxtypes.PatchData{Confidence: confidence(0.756), Assumptions: []string{"ids are unique", "the cache is not shared"}}
+	want := []string{"confidence: 0.76", "assumes: ids are unique", "assumes: the cache is not shared"}
+	if got := confidenceNote(data); !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if got := confidenceNote(ctxtypes.PatchData{}); len(got) != 0 {
+		t.Errorf("got %q without an assessment", got)
+	}
+	if got := formatConfidence(nil); got != "unknown" {
+		t.Errorf("got %q for a missing confidence", got)
+	}
+}
+
+func TestPatchBundleRecordsConfidence(t *testing.T) {
+	b := patchBundle{}
+	b.add("a.go", "a.go", ctxtypes.FileOperationUpdate, "patch a", "package a\n", ctxtypes.PatchData{Confidence: confidence(0.4), Assumptions: []string{"a is unused"}})
+	b.add("b.go", "b.go", ctxtypes.FileOperationCreate, "patch b", "", ctxtypes.PatchData{})
+
+	dir := filepath.Join(t.TempDir(), "bundle")
+	if err := b.write(dir, "prompt"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, bundleManifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest bundleManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, created := manifest.Patches[0], manifest.Patches[1]
+	if updated.Confidence == nil || *updated.Confidence != 0.4 || !slices.Equal(updated.Assumptions, []string{"a is unused"}) {
+		t.Errorf("a.go is recorded as %+v", updated)
+	}
+	if created.Confidence != nil || created.Assumptions != nil {
+		t.Errorf("b.go is recorded as %+v, want no assessment", created)
+	}
+}
+
+func TestStripResponseDecoration(t *testing.T) {
+	decorated := "--- a/a_test.go\n+++ b/a_test.go\n@@ -1,2 +1,2 @@\n 1 | package a\n-2 | var x = 1\n+var x = 2\n"
+	want := "--- a/a_test.go\n+++ b/a_test.go\n@@ -1,2 +1,2 @@\n package a\n-var x = 1\n+var x = 2\n"
+
+	resp := ctxtypes.StepFileWorkResponseSchema{
+		Data:       ctxtypes.PatchData{Patch: decorated},
+		Candidates: []ctxtypes.PatchData{{Patch: decorated}},
+		Tests:      []ctxtypes.PatchData{{Path: "a_test.go", Patch: decorated}},
+	}
+	stripResponseDecoration(&resp, ctxtypes.LineFormatPipe)
+
+	for name, got := range map[string]string{"data": resp.Data.Patch, "candidate": resp.Candidates[0].Patch, "test": resp.Tests[0].Patch} {
+		if got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+}
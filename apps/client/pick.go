@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+// pickEntry is a file or directory listed by the picker
+type pickEntry struct {
+	path  string
+	depth int
+	dir   bool
+}
+
+// pickEntries lists the directories and files of the context, each directory
+// before its content
+func pickEntries(ctx ctxtypes.ApplicationContext) []pickEntry {
+	seen := map[string]bool{}
+	entries := []pickEntry{}
+
+	ctxtypes.WalkFiles(ctx, func(p string, node *ctxtypes.FileSystemNode) {
+		parts := strings.Split(p, "/")
+		for i := 1; i < len(parts); i++ {
+			dir := strings.Join(parts[:i], "/")
+			if !seen[dir] {
+				seen[dir] = true
+				entries = append(entries, pickEntry{path: dir, depth: i - 1, dir: true})
+			}
+		}
+		if !seen[p] {
+			seen[p] = true
+			entries = append(entries, pickEntry{path: p, depth: len(parts) - 1, dir: node.Summary})
+		}
+	})
+
+	slices.SortFunc(entries, func(a, b pickEntry) int {
+		return slices.Compare(strings.Split(a.path, "/"), strings.Split(b.path, "/"))
+	})
+
+	return entries
+}
+
+// pickContext lets the user deselect files and directories of the context
+// before it is sent. It returns the deselected paths.
+func pickContext(reader *bufio.Reader, ctx ctxtypes.ApplicationContext) (map[string]bool, error) {
+	entries := pickEntries(ctx)
+	excluded := map[string]bool{}
+
+	for {
+		// the content of deselected directories is hidden
+		hidden := ""
+		for i, e := range entries {
+			if hidden != "" && strings.HasPrefix(e.path, hidden+"/") {
+				continue
+			}
+			hidden = ""
+
+			mark := "x"
+			if excluded[e.path] {
+				mark = " "
+				if e.dir {
+					hidden = e.path
+				}
+			}
+			name := path.Base(e.path)
+			if e.dir {
+				name += "/"
+			}
+			fmt.Printf("%4d [%s] %s%s\n", i+1, mark, strings.Repeat("  ", e.depth), name)
+		}
+
+		fmt.Print("Toggle entries (e.g. 2 5-7), empty to continue: ")
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		input = strings.TrimSpace(input)
+		if input == "" {
+			return excluded, nil
+		}
+
+		picked, err := parsePicks(input, len(entries))
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		for _, i := range picked {
+			p := entries[i].path
+			excluded[p] = !excluded[p]
+			if !excluded[p] {
+				delete(excluded, p)
+			}
+		}
+	}
+}
+
+// parsePicks parses space or comma separated 1-based entry numbers and ranges
+// into 0-based indexes
+func parsePicks(input string, count int) ([]int, error) {
+	picks := []int{}
+
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ' ' || r == ',' }) {
+		from, to, isRange := strings.Cut(field, "-")
+		if !isRange {
+			to = from
+		}
+
+		a, errA := strconv.Atoi(from)
+		b, errB := strconv.Atoi(to)
+		if errA != nil || errB != nil || a < 1 || b > count || a > b {
+			return nil, fmt.Errorf("invalid entry %q, expected a number or range between 1 and %d", field, count)
+		}
+		for i := a; i <= b; i++ {
+			picks = append(picks, i-1)
+		}
+	}
+
+	return picks, nil
+}
+
+// pruneContext removes the excluded paths, and the content of excluded directories, from the context
+func pruneContext(ctx *ctxtypes.ApplicationContext, excluded map[string]bool) {
+	isExcluded := func(p string) bool {
+		parts := strings.Split(p, "/")
+		for i := 1; i <= len(parts); i++ {
+			if excluded[strings.Join(parts[:i], "/")] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var prune func(prefix string, node *ctxtypes.FileSystemNode)
+	prune = func(prefix string, node *ctxtypes.FileSystemNode) {
+		for name, child := range node.Children {
+			p := path.Join(prefix, name)
+			if excluded[p] {
+				delete(node.Children, name)
+				continue
+			}
+			if child != nil && child.Directory {
+				prune(p, child)
+			}
+		}
+	}
+	for _, root := range ctx.FileSystem {
+		prune("", &root)
+	}
+
+	for p := range ctx.Files {
+		if isExcluded(strings.TrimSuffix(p, "/")) {
+			delete(ctx.Files, p)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	ctxtelemetry "github.com/cyber-nic/ctx/libs/telemetry"
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	"github.com/gorilla/websocket"
+)
+
+// requestPlan sends the plan request and waits for the implementation plan
+func requestPlan(ctx context.Context, conn *websocket.Conn, msg ctxtypes.CtxRequest) (ctxtypes.StepPlanData, error) {
+	ctx, span := ctxtelemetry.Tracer().Start(ctx, "plan")
+	defer span.End()
+
+	if err := sendRequest(ctx, conn, msg); err != nil {
+		return ctxtypes.StepPlanData{}, err
+	}
+
+	message, err := readResponse(conn)
+	if err != nil {
+		return ctxtypes.StepPlanData{}, err
+	}
+
+	var resp ctxtypes.StepPlanResponseSchema
+	if err := json.Unmarshal(message, &resp); err != nil {
+		return ctxtypes.StepPlanData{}, fmt.Errorf("failed to unmarshal plan response: %w", err)
+	}
+	if resp.Step != string(ctxtypes.CtxStepPlan) {
+		return ctxtypes.StepPlanData{}, fmt.Errorf("unexpected %q response to plan request", resp.Step)
+	}
+
+	return resp.Data, nil
+}
+
+// printPlan lists the plan summary and its numbered steps along with the files they affect
+func printPlan(plan ctxtypes.StepPlanData) {
+	fmt.Printf("Plan: %s\n", plan.Summary)
+	for i, step := range plan.Steps {
+		fmt.Printf("%d. %s\n", i+1, step.Description)
+		for _, f := range step.Files {
+			fmt.Printf("   - %s\n", f)
+		}
+	}
+}
+
+// confirmPlan asks the user to approve the plan. Anything but yes rejects it.
+func confirmPlan(reader *bufio.Reader) bool {
+	return confirm(reader, "Proceed with this plan? [y/N]: ")
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultReadConcurrency is the number of files read at once, see -read-concurrency
+const defaultReadConcurrency = 8
+
+// binarySniffLen is how much of a file is checked for NUL bytes, like git does
+const binarySniffLen = 8000
+
+// readResult is the outcome of reading a single file
+type readResult struct {
+	path    string
+	content string
+	err     error
+}
+
+// readFiles reads the files concurrently using a pool of concurrency workers.
+// Files larger than maxSize bytes (when > 0) are skipped, as are files not read
+// before the deadline elapses. Skipped files are logged and left out of the result.
+func readFiles(paths []string, maxSize int64, concurrency int, deadline time.Duration) map[string]string {
+	contents := map[string]string{}
+	if len(paths) == 0 {
+		return contents
+	}
+
+	queue := make(chan string, len(paths))
+	for _, p := range paths {
+		queue <- p
+	}
+	close(queue)
+
+	// buffered so that workers never block once the deadline has passed
+	results := make(chan readResult, len(paths))
+
+	var wg sync.WaitGroup
+	for w := 0; w < max(concurrency, 1) && w < len(paths); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range queue {
+				content, err := readFileCapped(p, maxSize)
+				results <- readResult{path: p, content: content, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	timeout := time.After(deadline)
+	for {
+		select {
+		case res, ok := <-results:
+			if !ok {
+				return contents
+			}
+			if res.err != nil {
+				log.Warn().Err(res.err).Str("file", res.path).Msg("Skipping context file")
+				continue
+			}
+			contents[res.path] = res.content
+		case <-timeout:
+			for _, p := range paths {
+				if _, ok := contents[p]; !ok {
+					log.Warn().Str("file", p).Dur("deadline", deadline).Msg("Skipping context file, read deadline exceeded")
+				}
+			}
+			return contents
+		}
+	}
+}
+
+// readFullContent reads the content of every file of the context, skipping
+// skipped, binary and lock files. Files are added in path order until budget bytes
+// (when > 0) are used, so the result doesn't depend on the order reads complete in.
+func readFullContent(ctx ctxtypes.ApplicationContext, budget, maxSize int64, concurrency int, deadline time.Duration) map[string]string {
+	paths := []string{}
+	ctxtypes.WalkFiles(ctx, func(p string, node *ctxtypes.FileSystemNode) {
+		if !node.Skip && !node.Summary && node.Role != ctxtypes.RoleLockfile {
+			paths = append(paths, p)
+		}
+	})
+	sort.Strings(paths)
+
+	read := readFiles(paths, maxSize, concurrency, deadline)
+
+	contents := map[string]string{}
+	used, over := int64(0), 0
+	for _, p := range paths {
+		content, ok := read[p]
+		if !ok || strings.IndexByte(content[:min(len(content), binarySniffLen)], 0) >= 0 {
+			continue
+		}
+		if budget > 0 && used+int64(len(content)) > budget {
+			over++
+			continue
+		}
+		contents[p] = content
+		used += int64(len(content))
+	}
+
+	if over > 0 {
+		log.Warn().Int("files", over).Int64("budget", budget).Msg("Full content budget exceeded, some contents left out")
+	}
+	log.Debug().Int("files", len(contents)).Int64("bytes", used).Msg("Full content read")
+
+	return contents
+}
+
+// splitBySize splits the paths into the files of at most threshold bytes and
+// the larger ones. Files that can't be stat'ed are left to the caller to report.
+// A threshold of 0 keeps every file in small.
+func splitBySize(paths []string, threshold int64) (small, large []string) {
+	if threshold <= 0 {
+		return paths, nil
+	}
+
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil && info.Size() > threshold {
+			large = append(large, p)
+			continue
+		}
+		small = append(small, p)
+	}
+	return small, large
+}
+
+// fileKeywords returns the keywords of the files, from the context tree or, for
+// the files of summarized directories, parsed on demand unless keywords are
+// disabled. Files that can't be parsed are left out.
+func fileKeywords(ctx ctxtypes.ApplicationContext, paths []string, opts parseOptions) map[string][]string {
+	known := ctxtypes.Flatten(ctx)
+
+	keywords := map[string][]string{}
+	for _, p := range paths {
+		if kws, ok := known[p]; ok {
+			keywords[p] = kws
+			continue
+		}
+
+		if opts.noKeywords {
+			keywords[p] = nil
+			continue
+		}
+
+		kws, err := parseFile(p, opts)
+		if err != nil {
+			log.Warn().Err(err).Str("file", p).Msg("Skipping context file, unable to extract keywords")
+			continue
+		}
+		keywords[p] = kws
+	}
+	return keywords
+}
+
+// readFileCapped reads a file unless it exceeds maxSize bytes
+func readFileCapped(path string, maxSize int64) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if maxSize > 0 && info.Size() > maxSize {
+		return "", fmt.Errorf("file size %d exceeds %d bytes", info.Size(), maxSize)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
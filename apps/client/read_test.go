@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+func TestReadFullContent(t *testing.T) {
+	tempRepo(t, map[string]string{
+		"a.go":                    "package a // 20 bytes",
+		"b/b.go":                  "package b // 20 bytes",
+		"b/c.go":                  "package c // 20 bytes",
+		"d.txt":                   "3b\n",
+		"big.go":                  "package big\n" + strings.Repeat("// padding\n", 100),
+		"image.dat":               "GIF89a\x00\x01",
+		"go.sum":                  "example.com/m v1.0.0 h1:abc=\n",
+		"node_modules/x/index.js": "module.exports = {}\n",
+	})
+	root, _ := walkTree(t, parseOptions{})
+	appCtx := ctxtypes.ApplicationContext{FileSystem: map[string]ctxtypes.FileSystemNode{ctxtypes.RootKey: root}}
+
+	// files are taken in path order within the budget, smaller ones after those over it included
+	want := map[string]string{
+		"a.go":   "package a // 20 bytes",
+		"b/b.go": "package b // 20 bytes",
+		"d.txt":  "3b\n",
+	}
+	for _, concurrency := range []int{1, 2, 8} {
+		for range 5 {
+			got := readFullContent(appCtx, 45, 512, concurrency, 5*time.Second)
+			if !maps.Equal(got, want) {
+				t.Fatalf("concurrency %d: got %q, want %q", concurrency, contentPaths(got), contentPaths(want))
+			}
+		}
+	}
+
+	// without a budget, every text file within the size limit
+	got := readFullContent(appCtx, 0, 512, 4, 5*time.Second)
+	if _, ok := got["b/c.go"]; !ok || len(got) != 4 {
+		t.Errorf("got %q without a budget", contentPaths(got))
+	}
+}
+
+// contentPaths returns the sorted paths of the contents
+func contentPaths(contents map[string]string) []string {
+	return slices.Sorted(maps.Keys(contents))
+}
+
+func TestReadFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.go")
+	writeTestFile(t, path, "package a\n")
+
+	if got := readFiles([]string{path, filepath.Join(dir, "missing.go")}, 0, 2, 5*time.Second); len(got) != 1 || got[path] != "package a\n" {
+		t.Errorf("got %q", got)
+	}
+	if got := readFiles(nil, 0, 2, time.Second); len(got) != 0 {
+		t.Errorf("got %q for no files", got)
+	}
+}
+
+// mediumRepo writes n files of about 8KB and returns a context listing them
+func mediumRepo(b *testing.B, n int) ctxtypes.ApplicationContext {
+	b.Helper()
+	dir := b.TempDir()
+	files := map[string][]string{}
+	content := []byte("package medium\n" + strings.Repeat("// some comment padding the file\n", 250))
+	for i := range n {
+		path := filepath.Join(dir, fmt.Sprintf("pkg%d", i%20), fmt.Sprintf("file%d.go", i))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			b.Fatal(err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			b.Fatal(err)
+		}
+		files[path] = nil
+	}
+	return ctxtypes.ApplicationContext{Files: files}
+}
+
+func BenchmarkReadFullContent(b *testing.B) {
+	appCtx := mediumRepo(b, 2000)
+
+	for _, concurrency := range []int{1, defaultReadConcurrency} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			for range b.N {
+				if got := readFullContent(appCtx, 0, 0, concurrency, time.Minute); len(got) != len(appCtx.Files) {
+					b.Fatalf("read %d files of %d", len(got), len(appCtx.Files))
+				}
+			}
+		})
+	}
+}
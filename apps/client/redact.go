@@ -0,0 +1,234 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	"github.com/rs/zerolog/log"
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// node kinds redacted by -redact, across the supported grammars
+var (
+	redactStringKinds = map[string]bool{
+		"interpreted_string_literal": true,
+		"raw_string_literal":         true,
+		"string":                     true,
+		"template_string":            true,
+	}
+	redactCommentKinds = map[string]bool{
+		"comment": true,
+	}
+)
+
+var (
+	// the opening and closing delimiters of literals and comments are kept so the code stays well formed
+	stringOpenRegex   = regexp.MustCompile(`^[A-Za-z]*("""|'''|"|'|` + "`" + `)`)
+	stringCloseRegex  = regexp.MustCompile(`("""|'''|"|'|` + "`" + `)$`)
+	commentOpenRegex  = regexp.MustCompile(`^(//|#|/\*+|<!--)`)
+	commentCloseRegex = regexp.MustCompile(`(\*/|-->)$`)
+)
+
+// redactOptions selects what is redacted from file contents, see -redact
+type redactOptions struct {
+	strings  bool
+	comments bool
+}
+
+// parseRedact parses a comma separated list of what to redact
+func parseRedact(s string) (redactOptions, error) {
+	opts := redactOptions{}
+
+	for _, name := range strings.Split(s, ",") {
+		switch strings.TrimSpace(name) {
+		case "strings":
+			opts.strings = true
+		case "comments":
+			opts.comments = true
+		case "":
+		default:
+			return opts, fmt.Errorf("unknown redaction %q, expected strings or comments", name)
+		}
+	}
+
+	return opts, nil
+}
+
+// redactor replaces string literals and comments with placeholders before
+// contents leave the machine, and restores them in the patches received. A
+// placeholder is derived from the text it replaces, so redacting the same
+// content twice yields the same result.
+type redactor struct {
+	opts      redactOptions
+	originals map[string]string
+}
+
+func newRedactor(opts redactOptions) *redactor {
+	return &redactor{opts: opts, originals: map[string]string{}}
+}
+
+func (r *redactor) enabled() bool {
+	return r.opts.strings || r.opts.comments
+}
+
+// redact returns the content with its literals and comments replaced. It
+// returns false when the file can't be parsed, in which case the content
+// must not be sent.
+func (r *redactor) redact(path string, content string) (string, bool) {
+	if !r.enabled() {
+		return content, true
+	}
+
+	language := getLanguage(path)
+	if language == nil || language.Inner == nil {
+		return "", false
+	}
+
+	parser := sitter.NewParser()
+	defer parser.Close()
+
+	if err := parser.SetLanguage(language); err != nil {
+		return "", false
+	}
+
+	code := []byte(content)
+	tree := parser.Parse(code, nil)
+	if tree == nil {
+		return "", false
+	}
+	defer tree.Close()
+
+	b := strings.Builder{}
+	last := uint(0)
+
+	var walk func(n *sitter.Node)
+	walk = func(n *sitter.Node) {
+		kind := n.Kind()
+		isString := r.opts.strings && redactStringKinds[kind]
+		isComment := r.opts.comments && redactCommentKinds[kind]
+
+		if isString || isComment {
+			open, close := stringOpenRegex, stringCloseRegex
+			if isComment {
+				open, close = commentOpenRegex, commentCloseRegex
+			}
+
+			b.Write(code[last:n.StartByte()])
+			b.WriteString(r.replace(string(code[n.StartByte():n.EndByte()]), open, close))
+			last = n.EndByte()
+			return
+		}
+
+		for i := uint(0); i < n.NamedChildCount(); i++ {
+			if child := n.NamedChild(i); child != nil {
+				walk(child)
+			}
+		}
+	}
+	walk(tree.RootNode())
+	b.Write(code[last:])
+
+	return b.String(), true
+}
+
+// replace substitutes each line of text, delimiters aside, with a placeholder
+// so the line count of the content is unchanged
+func (r *redactor) replace(text string, open, close *regexp.Regexp) string {
+	lines := strings.Split(text, "\n")
+
+	for i, line := range lines {
+		prefix, suffix := "", ""
+		if i == 0 {
+			prefix = open.FindString(line)
+		}
+		if i == len(lines)-1 {
+			suffix = close.FindString(line[len(prefix):])
+		}
+
+		inner := line[len(prefix) : len(line)-len(suffix)]
+		if strings.TrimSpace(inner) == "" {
+			continue
+		}
+
+		sum := sha256.Sum256([]byte(inner))
+		placeholder := "__CTX_" + hex.EncodeToString(sum[:6]) + "__"
+		r.originals[placeholder] = inner
+
+		lines[i] = prefix + placeholder + suffix
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// contents redacts every file content, dropping those that can't be redacted
+func (r *redactor) contents(contents map[string]string) map[string]string {
+	if !r.enabled() || contents == nil {
+		return contents
+	}
+
+	redacted := make(map[string]string, len(contents))
+	for p, content := range contents {
+		if c, ok := r.redact(p, content); ok {
+			redacted[p] = c
+		} else {
+			log.Warn().Str("file", p).Msg("Unable to redact, content withheld")
+		}
+	}
+	return redacted
+}
+
+// restore puts the original text back in place of the placeholders
+func (r *redactor) restore(text string) string {
+	if len(r.originals) == 0 {
+		return text
+	}
+
+	pairs := make([]string, 0, 2*len(r.originals))
+	for placeholder, original := range r.originals {
+		pairs = append(pairs, placeholder, original)
+	}
+
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// context returns a copy of the context with redacted file contents
+func (r *redactor) context(ctx ctxtypes.ApplicationContext) ctxtypes.ApplicationContext {
+	ctx.FileContents = r.contents(ctx.FileContents)
+	return ctx
+}
+
+// restoreResponse restores the originals in the patches and edits of a work response
+func (r *redactor) restoreResponse(resp ctxtypes.StepFileWorkResponseSchema) ctxtypes.StepFileWorkResponseSchema {
+	if len(r.originals) == 0 {
+		return resp
+	}
+
+	resp.Data.Patch = r.restore(resp.Data.Patch)
+
+	candidates := make([]ctxtypes.PatchData, len(resp.Candidates))
+	for i, c := range resp.Candidates {
+		c.Patch = r.restore(c.Patch)
+		candidates[i] = c
+	}
+	resp.Candidates = candidates
+
+	tests := make([]ctxtypes.PatchData, len(resp.Tests))
+	for i, t := range resp.Tests {
+		t.Patch = r.restore(t.Patch)
+		tests[i] = t
+	}
+	resp.Tests = tests
+
+	edits := make([]ctxtypes.TextEdit, len(resp.Edits))
+	for i, e := range resp.Edits {
+		e.NewText = r.restore(e.NewText)
+		edits[i] = e
+	}
+	resp.Edits = edits
+
+	return resp
+}
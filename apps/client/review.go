@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	ctxignore "github.com/cyber-nic/ctx/libs/ignore"
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	ctxutils "github.com/cyber-nic/ctx/libs/utils"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultReviewPrompt = "Review the staged changes"
+
+// runReview implements the review command, which asks the server to review the
+// staged changes. The context only holds the staged files and their neighbors,
+// the files sharing the most keywords with them.
+func runReview(args []string) {
+	fs := flag.NewFlagSet("review", flag.ExitOnError)
+	var debug = fs.Bool("debug", false, "enable debug mode")
+	var addr = fs.String("addr", "", "server address, see the main command (env CTX_ADDR)")
+	var rootFlag = fs.String("root", "", "repo root, detected from .git or go.mod when empty")
+	var prompt = fs.String("prompt", defaultReviewPrompt, "review instructions")
+	var neighbors = fs.Int("neighbors", 5, "number of related files added as context")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s review [flags]\n", filepath.Base(os.Args[0]))
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	ctxutils.ConfigLogging(debug)
+
+	root, _, err := enterRepoRoot(*rootFlag)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error locating repo root")
+	}
+
+	staged, err := stagedFiles()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error listing staged files")
+	}
+	if len(staged) == 0 {
+		log.Info().Msg("Nothing staged to review")
+		return
+	}
+
+	diff, err := exec.Command("git", "diff", "--cached").Output()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error reading staged changes")
+	}
+
+	cfg := loadConfig()
+	opts := parseOptions{maxFileSize: 1 << 20, maxLines: 20000, chunkSize: 256 << 10, concurrency: defaultParseConcurrency}
+	opts.languages = resolveLimits(cfg, opts, false, false)
+
+	appCtx, _, err := buildAppContext(root, ctxignore.Options{}, opts, contextFormatFlat, 0)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error getting folder structure")
+	}
+
+	// restrict the context to the staged files and their neighbors
+	related := keywordNeighbors(appCtx.Files, staged, *neighbors)
+	files := map[string][]string{}
+	for _, p := range append(staged, related...) {
+		files[p] = appCtx.Files[p]
+	}
+	appCtx.Files = files
+
+	// staged files are reviewed as staged, not as in the working tree
+	for _, p := range staged {
+		content, err := exec.Command("git", "show", ":"+p).Output()
+		if err != nil {
+			log.Warn().Err(err).Str("file", p).Msg("Error reading staged file")
+			continue
+		}
+		appCtx.FileContents[p] = string(content)
+	}
+	for p, content := range readFiles(related, 256<<10, defaultReadConcurrency, 10*time.Second) {
+		appCtx.FileContents[p] = content
+	}
+	log.Debug().Strs("staged", staged).Strs("neighbors", related).Msg("review context")
+
+	macAddr, err := getMacAddr()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error getting MAC address")
+	}
+
+	serverAddr := resolveAddr("", cfg)
+	if *addr != "" {
+		serverAddr = *addr
+	}
+	wsconn, err := serverURL(serverAddr)
+	if err != nil {
+		log.Fatal().Err(err).Msg("server address")
+	}
+
+	ws, _, err := websocket.DefaultDialer.Dial(wsconn.String(), nil)
+	if err != nil {
+		log.Fatal().Err(err).Msg("dial")
+	}
+	defer ws.Close()
+
+	msg := ctxtypes.CtxRequest{
+		ClientID:   macAddr,
+		Step:       ctxtypes.CtxStepReview,
+		Context:    appCtx,
+		UserPrompt: *prompt,
+		Diff:       string(diff),
+	}
+	if err := sendRequest(context.Background(), ws, msg); err != nil {
+		log.Fatal().Err(err).Msg("Unable to request review")
+	}
+
+	message, err := readResponse(ws)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Error reading review")
+	}
+
+	var resp ctxtypes.StepReviewResponseSchema
+	if err := json.Unmarshal(message, &resp); err != nil {
+		log.Fatal().Err(err).Msg("Error unmarshalling JSON")
+	}
+
+	printReview(resp.Data.Comments, appCtx.FileContents)
+}
+
+// stagedFiles lists the files added, copied, modified or renamed in the index
+func stagedFiles() ([]string, error) {
+	out, err := exec.Command("git", "diff", "--cached", "--name-only", "--diff-filter=ACMR").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// keywordNeighbors returns up to n files, other than those given, ranked by the
+// number of keywords they share with them
+func keywordNeighbors(files map[string][]string, paths []string, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	keywords := map[string]bool{}
+	own := map[string]bool{}
+	for _, p := range paths {
+		own[p] = true
+		for _, k := range files[p] {
+			keywords[k] = true
+		}
+	}
+
+	scores := map[string]int{}
+	for p, kws := range files {
+		if own[p] {
+			continue
+		}
+		for _, k := range kws {
+			if keywords[k] {
+				scores[p]++
+			}
+		}
+	}
+
+	ranked := make([]string, 0, len(scores))
+	for p := range scores {
+		ranked = append(ranked, p)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if scores[ranked[i]] != scores[ranked[j]] {
+			return scores[ranked[i]] > scores[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// reviewContextLines is the number of lines printed around a commented line
+const reviewContextLines = 2
+
+// printReview renders the comments inline, below the line of the file they
+// target along with the surrounding lines. Comments are ordered by file and line.
+func printReview(comments []ctxtypes.ReviewComment, contents map[string]string) {
+	sort.SliceStable(comments, func(i, j int) bool {
+		if comments[i].Path != comments[j].Path {
+			return comments[i].Path < comments[j].Path
+		}
+		return comments[i].Line < comments[j].Line
+	})
+
+	for _, c := range comments {
+		severity := c.Severity
+		if severity == "" {
+			severity = ctxtypes.ReviewSeverityInfo
+		}
+		fmt.Printf("%s:%d: [%s] %s\n", c.Path, c.Line, severity, c.Message)
+
+		content, ok := contents[c.Path]
+		if !ok {
+			continue
+		}
+		lines := strings.Split(content, "\n")
+		if c.Line < 1 || c.Line > len(lines) {
+			continue
+		}
+
+		from := max(c.Line-reviewContextLines, 1)
+		to := min(c.Line+reviewContextLines, len(lines))
+		for n := from; n <= to; n++ {
+			marker := " "
+			if n == c.Line {
+				marker = ">"
+			}
+			fmt.Printf("%s %5d | %s\n", marker, n, lines[n-1])
+			if n == c.Line {
+				fmt.Printf("        | ^ %s\n", c.Message)
+			}
+		}
+		fmt.Println()
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// repoRoot returns the directory the context is built from. It is flagRoot when
+// set, otherwise the closest ancestor of dir holding .git, otherwise the closest
+// one holding go.mod, otherwise dir itself.
+func repoRoot(dir string, flagRoot string) (string, error) {
+	if flagRoot != "" {
+		return filepath.Abs(flagRoot)
+	}
+
+	for _, marker := range []string{".git", "go.mod"} {
+		if root, ok := findAncestorWith(dir, marker); ok {
+			return root, nil
+		}
+	}
+
+	return dir, nil
+}
+
+// findAncestorWith walks up from dir looking for a directory containing name
+func findAncestorWith(dir string, name string) (string, bool) {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return dir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// enterRepoRoot changes the working directory to the repo root, so that every
+// path the client handles is relative to it. It returns the root and the
+// directory the command was invoked from.
+func enterRepoRoot(flagRoot string) (root string, invokedFrom string, err error) {
+	invokedFrom, err = os.Getwd()
+	if err != nil {
+		return "", "", err
+	}
+
+	root, err = repoRoot(invokedFrom, flagRoot)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := os.Chdir(root); err != nil {
+		return "", "", err
+	}
+
+	return root, invokedFrom, nil
+}
+
+// fromInvocation resolves a path given on the command line against the
+// directory the command was invoked from, relative to the root when possible.
+func fromInvocation(root, invokedFrom, p string) string {
+	if p == "" {
+		return p
+	}
+	p = absFrom(invokedFrom, p)
+	if rel, err := filepath.Rel(root, p); err == nil {
+		return rel
+	}
+	return p
+}
+
+// absFrom makes a path given on the command line absolute, resolving it against
+// the directory the command was invoked from. Used for output locations.
+func absFrom(invokedFrom, p string) string {
+	if p == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(invokedFrom, p)
+}
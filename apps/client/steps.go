@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultSteps = "load,select,work"
+
+// clientSteps are the phases the client runs, see -steps
+type clientSteps struct {
+	load      bool
+	plan      bool
+	selection bool
+	work      bool
+}
+
+// parseSteps parses a comma separated list of steps
+func parseSteps(s string) (clientSteps, error) {
+	steps := clientSteps{}
+
+	for _, name := range strings.Split(s, ",") {
+		switch strings.TrimSpace(name) {
+		case string(ctxtypes.CtxStepLoadContext):
+			steps.load = true
+		case string(ctxtypes.CtxStepPlan):
+			steps.plan = true
+		case string(ctxtypes.CtxStepFileSelection):
+			steps.selection = true
+		case string(ctxtypes.CtxStepCodeWork):
+			steps.work = true
+		case "":
+		default:
+			return steps, fmt.Errorf("unknown step %q, expected load, plan, select or work", name)
+		}
+	}
+
+	if !steps.selection && !steps.work {
+		return steps, fmt.Errorf("steps %q run neither select nor work", s)
+	}
+
+	return steps, nil
+}
+
+// seedSelection builds the selection from a list of files instead of asking the
+// model. Existing files are updated, the others created.
+func seedSelection(files []string) ctxtypes.StepFileSelectFiles {
+	selection := ctxtypes.StepFileSelectFiles{}
+
+	for _, f := range files {
+		if f = strings.TrimSpace(f); f == "" {
+			continue
+		}
+
+		op := ctxtypes.FileOperationUpdate
+		if _, err := os.Stat(f); os.IsNotExist(err) {
+			op = ctxtypes.FileOperationCreate
+		}
+		selection.Files = append(selection.Files, ctxtypes.StepFileSelectItem{
+			Operation: op,
+			Path:      f,
+			Reason:    "provided on the command line",
+		})
+	}
+
+	return selection
+}
+
+// logBudget reports the tokens left in the client's budget, when the server enforces one
+func logBudget(b *ctxtypes.TokenBudget) {
+	if b == nil {
+		return
+	}
+	log.Info().Int("remaining", b.Remaining()).Int("limit", b.Limit).Msg("Token budget")
+}
+
+// missingFiles returns the files that are neither in the context nor in one of
+// its summarized directories, e.g. ignored or misspelled files
+func missingFiles(ctx ctxtypes.ApplicationContext, summarized map[string][]string, files []string) []string {
+	known := map[string]bool{}
+	ctxtypes.WalkFiles(ctx, func(p string, node *ctxtypes.FileSystemNode) {
+		if !node.Summary {
+			known[p] = true
+		}
+	})
+	for _, dirFiles := range summarized {
+		for _, p := range dirFiles {
+			known[p] = true
+		}
+	}
+
+	missing := []string{}
+	for _, f := range files {
+		if !known[filepath.ToSlash(f)] {
+			missing = append(missing, f)
+		}
+	}
+	return missing
+}
+
+// printSelection lists the files to change and the additional context files
+func printSelection(selection ctxtypes.StepFileSelectFiles) {
+	for _, file := range selection.Files {
+		if file.Operation == ctxtypes.FileOperationMove {
+			fmt.Printf("move | %s -> %s: %s\n", file.Path, file.NewPath, file.Reason)
+		} else {
+			fmt.Printf("%s | %s: %s\n", file.Operation, file.Path, file.Reason)
+		}
+		printExplanation(file)
+	}
+
+	for _, file := range selection.Additional {
+		fmt.Printf("+ %s: %s\n", file.Path, file.Reason)
+		printExplanation(file)
+	}
+}
+
+// printExplanation prints the relevance and matched keywords of a file selected in explain mode
+func printExplanation(file ctxtypes.StepFileSelectItem) {
+	if len(file.MatchedKeywords) == 0 && file.Relevance == 0 {
+		return
+	}
+	fmt.Printf("    relevance %.2f, matched: %s\n", file.Relevance, strings.Join(file.MatchedKeywords, ", "))
+}
+
+// contextWarning describes why the context looks misconfigured: fewer than
+// minFiles files, or no keywords at all when they are expected. It is empty
+// when the context looks fine.
+func contextWarning(ctx ctxtypes.ApplicationContext, minFiles int, expectKeywords bool) string {
+	files, keywords := 0, 0
+	ctxtypes.WalkFiles(ctx, func(_ string, node *ctxtypes.FileSystemNode) {
+		if node.Skip {
+			return
+		}
+		if node.Summary {
+			files += node.FileCount
+		} else {
+			files++
+		}
+		keywords += len(node.Keywords)
+	})
+
+	switch {
+	case files < minFiles:
+		return fmt.Sprintf("the context holds too few files (%d), check the ignore rules and the directory ctx is run from", files)
+	case expectKeywords && keywords == 0:
+		return "no keywords were extracted from the context files, check the ignore rules and the parse limits"
+	}
+	return ""
+}
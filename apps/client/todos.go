@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"regexp"
+	"strings"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+const (
+	// maxTodosPerFile and maxTodoLen bound the payload of -todos
+	maxTodosPerFile = 20
+	maxTodoLen      = 120
+)
+
+// todoPattern matches a marker at the start of a comment, in any of the common
+// comment syntaxes, e.g. `// TODO(nic): retry` or `# FIXME handle errors`
+var todoPattern = regexp.MustCompile(`(?:^|\s)(?://+|#+|/\*+|\*+|--|;+|<!--)\s*(TODO|FIXME|HACK|XXX)\b(?:\([^)]*\))?:?\s*(.*)`)
+
+// fileTodos returns the TODO, FIXME, HACK and XXX comments of the file with
+// their 1-based line, at most maxTodosPerFile of them. Binary files have none.
+func fileTodos(filePath string) []ctxtypes.Todo {
+	code, err := os.ReadFile(filePath)
+	if err != nil || bytes.IndexByte(code[:min(len(code), binarySniffLen)], 0) >= 0 {
+		return nil
+	}
+
+	todos := []ctxtypes.Todo{}
+	scanner := bufio.NewScanner(bytes.NewReader(code))
+	scanner.Buffer(make([]byte, 0, 64<<10), len(code)+1)
+	for line := 1; scanner.Scan() && len(todos) < maxTodosPerFile; line++ {
+		m := todoPattern.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+
+		text := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(m[2]), "*/"))
+		text = strings.TrimSpace(strings.TrimSuffix(text, "-->"))
+		if r := []rune(text); len(r) > maxTodoLen {
+			text = string(r[:maxTodoLen]) + "…"
+		}
+		todos = append(todos, ctxtypes.Todo{Line: line, Kind: m[1], Text: text})
+	}
+
+	if len(todos) == 0 {
+		return nil
+	}
+	return todos
+}
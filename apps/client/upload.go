@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	ctxtelemetry "github.com/cyber-nic/ctx/libs/telemetry"
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+// uploadContents sends a manifest of the contents' hashes, then uploads only
+// the contents the server doesn't already have. It returns the path to hash
+// map to reference the contents in subsequent requests.
+func uploadContents(ctx context.Context, conn *websocket.Conn, clientID string, contents map[string]string) (map[string]string, error) {
+	ctx, span := ctxtelemetry.Tracer().Start(ctx, "upload")
+	defer span.End()
+
+	manifest := make(map[string]string, len(contents))
+	byHash := make(map[string]string, len(contents))
+	for path, content := range contents {
+		hash := ctxtypes.ContentHash(content)
+		manifest[path] = hash
+		byHash[hash] = content
+	}
+
+	if err := sendRequest(ctx, conn, ctxtypes.CtxRequest{
+		ClientID: clientID,
+		Step:     ctxtypes.CtxStepManifest,
+		Manifest: manifest,
+	}); err != nil {
+		return nil, err
+	}
+
+	var manifestResp ctxtypes.StepManifestResponseSchema
+	if err := readResponseInto(conn, &manifestResp); err != nil {
+		return nil, err
+	}
+
+	if len(manifestResp.Missing) == 0 {
+		log.Debug().Int("files", len(manifest)).Msg("all contents cached on server")
+		return manifest, nil
+	}
+
+	blobs := make(map[string]string, len(manifestResp.Missing))
+	for _, hash := range manifestResp.Missing {
+		content, ok := byHash[hash]
+		if !ok {
+			return nil, fmt.Errorf("server requested unknown hash %s", hash)
+		}
+		blobs[hash] = content
+	}
+
+	if err := sendRequest(ctx, conn, ctxtypes.CtxRequest{
+		ClientID: clientID,
+		Step:     ctxtypes.CtxStepUpload,
+		Blobs:    blobs,
+	}); err != nil {
+		return nil, err
+	}
+
+	var uploadResp ctxtypes.StepUploadResponseSchema
+	if err := readResponseInto(conn, &uploadResp); err != nil {
+		return nil, err
+	}
+	if uploadResp.Stored != len(blobs) {
+		return nil, fmt.Errorf("server stored %d of %d uploaded contents", uploadResp.Stored, len(blobs))
+	}
+
+	log.Debug().Int("files", len(manifest)).Int("uploaded", len(blobs)).Msg("contents uploaded")
+
+	return manifest, nil
+}
+
+// maxChunkRetries bounds how many times a chunk the server didn't take is resent
+const maxChunkRetries = 3
+
+// sendContext sends a load request. A context larger than chunkSize bytes is
+// sent in chunks, each acknowledged by the server, reporting the progress on
+// stderr. A chunk the server didn't take is resent from where it left off.
+func sendContext(ctx context.Context, conn *websocket.Conn, msg ctxtypes.CtxRequest, chunkSize int) error {
+	if chunkSize <= 0 {
+		return sendRequest(ctx, conn, msg)
+	}
+
+	data, err := json.Marshal(msg.Context)
+	if err != nil {
+		return fmt.Errorf("failed to marshal context: %w", err)
+	}
+	if len(data) <= chunkSize {
+		return sendRequest(ctx, conn, msg)
+	}
+
+	// chunk boundaries, never splitting a utf-8 sequence
+	offsets := []int{0}
+	for start := 0; start < len(data); {
+		end := min(start+chunkSize, len(data))
+		for end < len(data) && end > start+1 && !utf8.RuneStart(data[end]) {
+			end--
+		}
+		offsets = append(offsets, end)
+		start = end
+	}
+	total := len(offsets) - 1
+
+	retries := 0
+	for next := 0; next < total; {
+		if err := sendRequest(ctx, conn, ctxtypes.CtxRequest{
+			ClientID: msg.ClientID,
+			Step:     ctxtypes.CtxStepLoadChunk,
+			Chunk: &ctxtypes.ContextChunk{
+				Index: next,
+				Total: total,
+				Data:  string(data[offsets[next]:offsets[next+1]]),
+			},
+		}); err != nil {
+			return err
+		}
+
+		var ack ctxtypes.StepLoadChunkResponseSchema
+		if err := readResponseInto(conn, &ack); err != nil {
+			return err
+		}
+		if ack.Received < 0 || ack.Received > total {
+			return fmt.Errorf("server acknowledged %d of %d context chunks", ack.Received, total)
+		}
+
+		if ack.Received <= next {
+			retries++
+			if retries > maxChunkRetries {
+				return fmt.Errorf("server didn't take context chunk %d of %d", next+1, total)
+			}
+			log.Debug().Int("chunk", next).Int("resume", ack.Received).Msg("resending context chunks")
+		} else {
+			retries = 0
+		}
+
+		next = ack.Received
+		renderUpload("uploading context", offsets[next], len(data))
+	}
+	clearStatus()
+
+	log.Debug().Int("bytes", len(data)).Int("chunks", total).Msg("context uploaded")
+
+	return nil
+}
+
+// streamBatchSize is the number of tree nodes sent per load-nodes request
+const streamBatchSize = 500
+
+// treeStreamer sends the nodes of the tree to the server in batches as they are
+// walked, the server assembles them into the file system of the next load request
+type treeStreamer struct {
+	ctx      context.Context
+	conn     *websocket.Conn
+	clientID string
+	batch    map[string]ctxtypes.FileSystemNode
+	sent     int
+}
+
+func newTreeStreamer(ctx context.Context, conn *websocket.Conn, clientID string) *treeStreamer {
+	return &treeStreamer{ctx: ctx, conn: conn, clientID: clientID, batch: map[string]ctxtypes.FileSystemNode{}}
+}
+
+// add queues the node at the slash separated path, its children are sent on their own
+func (s *treeStreamer) add(p string, node ctxtypes.FileSystemNode) error {
+	node.Children = nil
+	s.batch[p] = node
+	if len(s.batch) < streamBatchSize {
+		return nil
+	}
+	return s.flush()
+}
+
+// flush sends the queued nodes
+func (s *treeStreamer) flush() error {
+	if len(s.batch) == 0 {
+		return nil
+	}
+
+	if err := sendRequest(s.ctx, s.conn, ctxtypes.CtxRequest{
+		ClientID: s.clientID,
+		Step:     ctxtypes.CtxStepLoadNodes,
+		Nodes:    s.batch,
+	}); err != nil {
+		return err
+	}
+
+	s.sent += len(s.batch)
+	log.Trace().Int("nodes", len(s.batch)).Int("sent", s.sent).Msg("tree nodes streamed")
+	s.batch = map[string]ctxtypes.FileSystemNode{}
+
+	return nil
+}
+
+// readResponseInto reads the next step response and unmarshals it into v
+func readResponseInto(conn *websocket.Conn, v any) error {
+	message, err := readResponse(conn)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if err := json.Unmarshal(message, v); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return nil
+}
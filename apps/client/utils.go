@@ -4,20 +4,33 @@ import (
 	"bufio"
 	"bytes"
 	"errors"
+	"flag"
+	"fmt"
 	"net"
-	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"unsafe"
 
+	tree_sitter_dockerfile "github.com/cyber-nic/ctx/apps/client/grammars/dockerfile"
+	"github.com/cyber-nic/ctx/apps/client/mapper"
+	ctxexcludes "github.com/cyber-nic/ctx/libs/excludes"
+	ctxignore "github.com/cyber-nic/ctx/libs/ignore"
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
 	"github.com/rs/zerolog/log"
-
 	sitter "github.com/tree-sitter/go-tree-sitter"
+	tree_sitter_c "github.com/tree-sitter/tree-sitter-c/bindings/go"
+	tree_sitter_cpp "github.com/tree-sitter/tree-sitter-cpp/bindings/go"
 	tree_sitter_go "github.com/tree-sitter/tree-sitter-go/bindings/go"
+	tree_sitter_java "github.com/tree-sitter/tree-sitter-java/bindings/go"
 	tree_sitter_javascript "github.com/tree-sitter/tree-sitter-javascript/bindings/go"
 	tree_sitter_python "github.com/tree-sitter/tree-sitter-python/bindings/go"
 	tree_sitter_typescript "github.com/tree-sitter/tree-sitter-typescript/bindings/go"
 )
 
+// docsPrefix is the virtual directory reference documents are exposed under
+const docsPrefix = "docs"
+
 // getMacAddr gets the MAC hardware
 // address of the host machine
 func getMacAddr() (string, error) {
@@ -33,59 +46,152 @@ func getMacAddr() (string, error) {
 	return "", errors.New("could not get MAC address")
 }
 
-func loadIgnoreList(ignoreFilePath string) []string {
-	ignoreList := make(map[string]struct{})
+// confirm asks a yes/no question. Anything but yes, including a read error, is a no.
+func confirm(reader *bufio.Reader, question string) bool {
+	fmt.Print(question)
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		log.Warn().Err(err).Msg("Error reading input, assuming no")
+		return false
+	}
 
-	file, err := os.Open(ignoreFilePath)
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// stringSliceFlag is a flag.Value collecting every occurrence of a repeatable flag
+type stringSliceFlag []string
+
+func (f *stringSliceFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringSliceFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// ignoreFlags are the ignore flags shared by the commands walking the tree
+type ignoreFlags struct {
+	patterns    stringSliceFlag
+	noDefaults  *bool
+	noGitignore *bool
+	noCtxignore *bool
+}
+
+// registerIgnoreFlags defines the ignore flags on fs
+func registerIgnoreFlags(fs *flag.FlagSet) *ignoreFlags {
+	f := &ignoreFlags{}
+	fs.Var(&f.patterns, "ignore", "additional ignore pattern, evaluated after ignore files (repeatable)")
+	f.noDefaults = fs.Bool("no-default-excludes", false, "don't ignore the default excludes, e.g. node_modules or .git")
+	f.noGitignore = fs.Bool("no-gitignore", false, "don't read the .gitignore files of the tree")
+	f.noCtxignore = fs.Bool("no-ctxignore", false, "don't read the .ctxignore files of the tree")
+	return f
+}
+
+// options returns the ignore options the flags describe, excludes aside
+func (f *ignoreFlags) options() ctxignore.Options {
+	return ctxignore.Options{Patterns: f.patterns, NoDefaults: *f.noDefaults, NoGitignore: *f.noGitignore, NoCtxignore: *f.noCtxignore}
+}
+
+// newIgnoreSet loads the ignore set of root: the default excludes and those
+// of the detected project types, adjusted by the user's override file, the
+// .gitignore and .ctxignore files, then the -ignore patterns. The ignore files
+// of subdirectories are loaded by the walk, see getContextFileTree.
+func newIgnoreSet(root string, opts ctxignore.Options) (*ctxignore.IgnoreSet, error) {
+	opts, err := withExcludes(root, opts)
+	if err != nil {
+		return nil, err
+	}
+	return ctxignore.NewIgnoreSet(root, opts)
+}
+
+// effectiveIgnore loads the ignore set of root like newIgnoreSet, along with
+// the ignore files of every subdirectory, for matching paths without a walk
+func effectiveIgnore(root string, opts ctxignore.Options) (ctxignore.IgnoreSet, error) {
+	opts, err := withExcludes(root, opts)
 	if err != nil {
-		log.Warn().Msgf("Failed to load ignore file: %s", ignoreFilePath)
-		return []string{}
+		return ctxignore.IgnoreSet{}, err
 	}
-	defer file.Close()
+	return ctxignore.EffectiveIgnore(root, opts)
+}
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line != "" && !strings.HasPrefix(line, "#") {
-			ignoreList[line] = struct{}{}
+// withExcludes sets the default excludes of the options, unless disabled
+func withExcludes(root string, opts ctxignore.Options) (ctxignore.Options, error) {
+	if !opts.NoDefaults {
+		excludes, err := ctxexcludes.Effective(root)
+		if err != nil {
+			return opts, fmt.Errorf("failed to load excludes override: %w", err)
 		}
+		opts.Excludes = excludes
+	}
+	return opts, nil
+}
+
+// docVirtualPath returns the path a reference document is exposed under in the context
+func docVirtualPath(p string) string {
+	p = filepath.ToSlash(filepath.Clean(p))
+	if filepath.IsAbs(p) || strings.HasPrefix(p, "../") {
+		p = filepath.Base(p)
+	}
+	return path.Join(docsPrefix, p)
+}
+
+// withoutReferences drops the items that point at reference documents
+func withoutReferences(items []ctxtypes.StepFileSelectItem, references []string) []ctxtypes.StepFileSelectItem {
+	if len(references) == 0 {
+		return items
 	}
 
-	if err := scanner.Err(); err != nil {
-		log.Warn().Err(err).Msgf("Error reading ignore file: %s", ignoreFilePath)
+	refs := map[string]bool{}
+	for _, r := range references {
+		refs[r] = true
 	}
 
-	keys := make([]string, 0, len(ignoreList))
-	// convert hash to array
-	for key := range ignoreList {
-		keys = append(keys, key)
+	kept := []ctxtypes.StepFileSelectItem{}
+	for _, item := range items {
+		if refs[item.Path] || refs[item.TargetPath()] {
+			log.Warn().Str("file", item.Path).Msg("Ignoring reference document selected by the model")
+			continue
+		}
+		kept = append(kept, item)
 	}
+	return kept
+}
 
-	return keys
+// grammars are the tree-sitter grammars by file extension
+var grammars = map[string]func() unsafe.Pointer{
+	".go":   tree_sitter_go.Language,
+	".java": tree_sitter_java.Language,
+	".c":    tree_sitter_c.Language,
+	".h":    tree_sitter_c.Language,
+	".cc":   tree_sitter_cpp.Language,
+	".cpp":  tree_sitter_cpp.Language,
+	".cxx":  tree_sitter_cpp.Language,
+	".hpp":  tree_sitter_cpp.Language,
+	".js":   tree_sitter_javascript.Language,
+	".jsx":  tree_sitter_javascript.Language,
+	".py":   tree_sitter_python.Language,
+	".ts":   tree_sitter_typescript.LanguageTypescript,
+	".tsx":  tree_sitter_typescript.LanguageTypescript,
 }
 
+// dockerfileGrammar is the grammar of Dockerfiles, matched by name rather than
+// extension, see mapper.IsDockerfile
+var dockerfileGrammar = tree_sitter_dockerfile.Language
+
+// getLanguage returns the grammar of the file, nil for unsupported extensions.
+// The grammar of a supported extension that failed to load has a nil Inner.
 func getLanguage(path string) *sitter.Language {
-	// return docker if filepath begins with Dockerfile"
-	if strings.HasPrefix(path, "Dockerfile") {
-		return sitter.NewLanguage(tree_sitter_go.Language())
+	grammar, ok := grammars[filepath.Ext(path)]
+	if mapper.IsDockerfile(path) {
+		grammar, ok = dockerfileGrammar, true
 	}
-
-	ext := filepath.Ext(path)
-
-	switch ext {
-	case ".go":
-		return sitter.NewLanguage(tree_sitter_go.Language())
-	case ".jsx":
-		return sitter.NewLanguage(tree_sitter_javascript.Language())
-	case ".js":
-		return sitter.NewLanguage(tree_sitter_javascript.Language())
-	case ".py":
-		return sitter.NewLanguage(tree_sitter_python.Language())
-	case ".tsx":
-		return sitter.NewLanguage(tree_sitter_typescript.LanguageTypescript())
-	case ".ts":
-		return sitter.NewLanguage(tree_sitter_typescript.LanguageTypescript())
-	default:
+	if !ok {
 		return nil
 	}
+	return sitter.NewLanguage(grammar())
 }
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"unsafe"
+
+	tree_sitter_dockerfile "github.com/cyber-nic/ctx/apps/client/grammars/dockerfile"
+	"github.com/cyber-nic/ctx/apps/client/mapper"
+	tree_sitter_c "github.com/tree-sitter/tree-sitter-c/bindings/go"
+	tree_sitter_cpp "github.com/tree-sitter/tree-sitter-cpp/bindings/go"
+)
+
+func TestGetLanguage(t *testing.T) {
+	tests := []struct {
+		path    string
+		grammar unsafe.Pointer
+		name    string
+	}{
+		{"src/user.c", tree_sitter_c.Language(), "c"},
+		{"include/user.h", tree_sitter_c.Language(), "c"},
+		{"src/store.cpp", tree_sitter_cpp.Language(), "cpp"},
+		{"src/store.cc", tree_sitter_cpp.Language(), "cpp"},
+		{"src/store.cxx", tree_sitter_cpp.Language(), "cpp"},
+		{"include/store.hpp", tree_sitter_cpp.Language(), "cpp"},
+		// matched by name, see mapper.IsDockerfile
+		{"Dockerfile", tree_sitter_dockerfile.Language(), "dockerfile"},
+		{"deploy/Dockerfile.prod", tree_sitter_dockerfile.Language(), "dockerfile"},
+		{"app.Dockerfile", tree_sitter_dockerfile.Language(), "dockerfile"},
+	}
+	for _, tt := range tests {
+		language := getLanguage(tt.path)
+		if language == nil || unsafe.Pointer(language.Inner) != tt.grammar {
+			t.Errorf("%s doesn't use the %s grammar", tt.path, tt.name)
+		}
+		if name := mapper.LanguageName(tt.path); name != tt.name {
+			t.Errorf("%s is mapped as %q, want %q", tt.path, name, tt.name)
+		}
+	}
+
+	if getLanguage("README.md") != nil {
+		t.Error("README.md has a grammar")
+	}
+}
+
+func TestParseFileDockerfile(t *testing.T) {
+	tempRepo(t, map[string]string{"Dockerfile.prod": "FROM nginx:1.27 AS web\nEXPOSE 80\nRUN apt-get install curl\n"})
+
+	// parsed with the Dockerfile grammar, not the Go one
+	got, err := parseFile("./Dockerfile.prod", parseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"80", "nginx:1.27", "web"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if !hasExtractor("Dockerfile.prod") {
+		t.Error("Dockerfile.prod has no extractor")
+	}
+}
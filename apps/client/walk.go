@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	"github.com/rs/zerolog/log"
+)
+
+// defaultParseConcurrency is the number of files parsed at once, see -parse-concurrency
+var defaultParseConcurrency = runtime.NumCPU()
+
+// fileJob is a file of the tree waiting for its keywords
+type fileJob struct {
+	path string
+	// node is the file's node in the tree, filled in by the worker parsing it
+	node *ctxtypes.FileSystemNode
+}
+
+// parseFiles fills in the nodes of the walked files using a pool of
+// opts.concurrency workers. Each worker only writes the nodes of its files, so
+// the tree is identical whatever the order files complete in. Streamed nodes are
+// sent one at a time and the first streaming error stops the remaining files.
+func parseFiles(files []fileJob, opts parseOptions) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	queue := make(chan fileJob, len(files))
+	for _, f := range files {
+		queue <- f
+	}
+	close(queue)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		streamErr error
+	)
+	for w := 0; w < max(opts.concurrency, 1) && w < len(files); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range queue {
+				*f.node = fileNode(f.path, opts)
+				log.Debug().Str("path", f.path).Msg("Added to tree")
+
+				if opts.stream == nil {
+					continue
+				}
+				mu.Lock()
+				if streamErr == nil {
+					streamErr = opts.stream(filepath.ToSlash(f.path), *f.node)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return streamErr
+}
+
+// fileNode returns the node of the file at the path relative to the root,
+// with its keywords, role and, with opts.todos, its TODO comments
+func fileNode(relPath string, opts parseOptions) ctxtypes.FileSystemNode {
+	if opts.noKeywords {
+		return ctxtypes.FileSystemNode{}
+	}
+
+	role := ctxtypes.ClassifyRole(relPath)
+	if role == ctxtypes.RoleLockfile {
+		// lockfiles are only worth their pinned dependencies
+		keywords, err := lockfileKeywords(relPath)
+		if err != nil {
+			log.Debug().Err(err).Str("path", relPath).Msg("Skipped lockfile")
+		}
+		return ctxtypes.FileSystemNode{Keywords: keywords, Role: role}
+	}
+
+	// Parse the file for keywords
+	node := ctxtypes.FileSystemNode{}
+	var skipErr *skipError
+	if keywords, err := parseFile(relPath, opts); errors.As(err, &skipErr) {
+		log.Debug().Str("path", relPath).Str("reason", skipErr.reason).Msg("Skipped parsing")
+		node = ctxtypes.FileSystemNode{Skip: true, SkipReason: skipErr.reason}
+	} else if err == nil {
+		node.Keywords = keywords
+	}
+	node.Role = role
+	if opts.todos && !node.Skip {
+		node.Todos = fileTodos(relPath)
+	}
+	return node
+}
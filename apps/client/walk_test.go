@@ -0,0 +1,304 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"unsafe"
+
+	ctxexcludes "github.com/cyber-nic/ctx/libs/excludes"
+	ctxignore "github.com/cyber-nic/ctx/libs/ignore"
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	"github.com/rs/zerolog"
+)
+
+// parseCounter records the files handed to the parser by the walk
+type parseCounter struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (c *parseCounter) parsed(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paths = append(c.paths, path)
+}
+
+// sorted returns the parsed paths in order
+func (c *parseCounter) sorted() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Sorted(slices.Values(c.paths))
+}
+
+// walkTree walks the tree of the current directory, as the client does, parsing
+// 4 files at once unless opts sets the concurrency. The keyword cache of the
+// directory is used unless opts disables it. It returns the root node along with
+// the paths of the nodes streamed by the walk.
+func walkTree(t testing.TB, opts parseOptions) (ctxtypes.FileSystemNode, []string) {
+	t.Helper()
+	// the user's excludes override doesn't apply
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	ignores, err := newIgnoreSet(".", ctxignore.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	walked := []string{}
+	opts.stream = func(path string, _ ctxtypes.FileSystemNode) error {
+		mu.Lock()
+		defer mu.Unlock()
+		walked = append(walked, path)
+		return nil
+	}
+	if opts.concurrency == 0 {
+		opts.concurrency = 4
+	}
+
+	tree, err := getContextFileTree(".", ignores, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree[ctxtypes.RootKey], walked
+}
+
+func TestGetContextFileTreeSkipsIgnoredDirs(t *testing.T) {
+	files := map[string]string{
+		"main.go":          "package main\n\nfunc main() {}\n",
+		"store/store.go":   "package store\n\ntype Store struct{}\n",
+		".ctxignore":       "generated/\n",
+		"generated/gen.go": "package generated\n",
+	}
+	for i := range 50 {
+		files[fmt.Sprintf("node_modules/lib%d/index.js", i)] = "module.exports = function lib() {}\n"
+	}
+	files["node_modules/tool/main.go"] = "package tool\n"
+	tempRepo(t, files)
+
+	counter := &parseCounter{}
+	root, walked := walkTree(t, parseOptions{parsed: counter.parsed})
+
+	for _, dir := range []string{"node_modules", "generated"} {
+		node := root.Children[dir]
+		if node == nil || !node.Skip || !node.Directory {
+			t.Fatalf("%s is %+v, want a skipped directory", dir, node)
+		}
+		if len(node.Children) > 0 {
+			t.Errorf("%s has children %v", dir, node.Children)
+		}
+	}
+
+	// nothing below the ignored directories is walked or parsed
+	for _, p := range walked {
+		if strings.HasPrefix(p, "node_modules/") || strings.HasPrefix(p, "generated/") {
+			t.Errorf("walked %s", p)
+		}
+	}
+	if got, want := counter.sorted(), []string{"main.go", "store/store.go"}; !slices.Equal(got, want) {
+		t.Errorf("parsed %q, want %q", got, want)
+	}
+}
+
+func TestGetContextFileTreeSkipsDefaultExcludes(t *testing.T) {
+	excluded := []string{"node_modules", "dist", "pkg"}
+	files := map[string]string{"main.go": "package main\n"}
+	for _, dir := range excluded {
+		if !ctxexcludes.Excludes[dir] {
+			t.Fatalf("%s isn't a default exclude", dir)
+		}
+		files[dir+"/lib/index.js"] = "module.exports = {}\n"
+	}
+	tempRepo(t, files)
+
+	root, _ := walkTree(t, parseOptions{})
+
+	for _, dir := range excluded {
+		node := root.Children[dir]
+		if node == nil || !node.Skip {
+			t.Errorf("%s is %+v, want it skipped", dir, node)
+			continue
+		}
+		if _, ok := node.Children["lib"]; ok {
+			t.Errorf("%s/lib is in the tree", dir)
+		}
+	}
+	if node := root.Children["main.go"]; node == nil || node.Skip {
+		t.Errorf("main.go is %+v, want it in the tree", node)
+	}
+}
+
+func TestGetContextFileTreeSkipsUnavailableGrammar(t *testing.T) {
+	tempRepo(t, map[string]string{
+		"main.go": "package main\n\nfunc main() {}\n",
+		"app.py":  "def run():\n    pass\n",
+	})
+
+	// the .go grammar failed to load
+	loader := grammars[".go"]
+	grammars[".go"] = func() unsafe.Pointer { return nil }
+	t.Cleanup(func() { grammars[".go"] = loader })
+
+	counter := &parseCounter{}
+	root, _ := walkTree(t, parseOptions{parsed: counter.parsed})
+
+	if node := root.Children["main.go"]; node == nil || !node.Skip || node.SkipReason != "grammar unavailable" {
+		t.Errorf("main.go is %+v, want it skipped for its grammar", node)
+	}
+	if node := root.Children["app.py"]; node == nil || node.Skip || !slices.Contains(node.Keywords, "run") {
+		t.Errorf("app.py is %+v, want its keywords", node)
+	}
+	if got, want := counter.sorted(), []string{"app.py"}; !slices.Equal(got, want) {
+		t.Errorf("parsed %q, want %q", got, want)
+	}
+	if hasExtractor("main.go") {
+		t.Error("main.go has an extractor without its grammar")
+	}
+}
+
+func TestGetContextFileTreeNestsByBaseName(t *testing.T) {
+	tempRepo(t, map[string]string{
+		"a/b/c.go": "package b\n\nfunc Deep() {}\n",
+		"a/top.go": "package a\n",
+	})
+
+	root, walked := walkTree(t, parseOptions{})
+
+	a := root.Children["a"]
+	if a == nil || !a.Directory {
+		t.Fatalf("a is %+v, want a directory", a)
+	}
+	b := a.Children["b"]
+	if b == nil || !b.Directory {
+		t.Fatalf("a/b is %+v, want a directory", b)
+	}
+	c := b.Children["c.go"]
+	if c == nil || c.Directory || !slices.Contains(c.Keywords, "Deep") {
+		t.Fatalf("a/b/c.go is %+v, want the file under a -> b", c)
+	}
+	if got := keys(a.Children); !slices.Equal(got, []string{"b", "top.go"}) {
+		t.Errorf("a has children %q", got)
+	}
+	if got := keys(b.Children); !slices.Equal(got, []string{"c.go"}) {
+		t.Errorf("a/b has children %q", got)
+	}
+	for name := range root.Children {
+		if strings.Contains(name, "/") {
+			t.Errorf("the root has a child keyed %q", name)
+		}
+	}
+
+	// the streamed paths are relative to the root, see treeStreamer
+	if !slices.Contains(walked, "a/b/c.go") {
+		t.Errorf("a/b/c.go wasn't streamed in %q", walked)
+	}
+}
+
+// treePaths returns the sorted paths of the nodes below the root, joining the
+// keys of the nodes leading to each
+func treePaths(node ctxtypes.FileSystemNode, prefix string) []string {
+	paths := []string{}
+	for name, child := range node.Children {
+		p := prefix + name
+		paths = append(paths, p)
+		paths = append(paths, treePaths(*child, p+"/")...)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+func TestGetContextFileTreeShape(t *testing.T) {
+	tempRepo(t, map[string]string{
+		"src/app/main.go":      "package main\n\nfunc main() {}\n",
+		"src/app/handler/h.go": "package handler\n",
+		"src/lib.go":           "package src\n",
+		"README.md":            "# app\n",
+	})
+
+	root, walked := walkTree(t, parseOptions{})
+
+	// a node keyed by its relative path would show up with its parents repeated,
+	// .git is kept as a skipped node
+	want := []string{".git", "README.md", "src", "src/app", "src/app/handler", "src/app/handler/h.go", "src/app/main.go", "src/lib.go"}
+	if got := treePaths(root, ""); !slices.Equal(got, want) {
+		t.Errorf("got nodes %q, want %q", got, want)
+	}
+	// the tree holds the nodes streamed by the walk, at their path
+	for _, p := range walked {
+		if p != "." && !slices.Contains(want, p) {
+			t.Errorf("streamed %s, not in the tree", p)
+		}
+	}
+	if main := root.Children["src"].Children["app"].Children["main.go"]; main == nil || !slices.Contains(main.Keywords, "main") {
+		t.Errorf("src/app/main.go is %+v", main)
+	}
+}
+
+// keys returns the sorted names of the children
+func keys(children map[string]*ctxtypes.FileSystemNode) []string {
+	return slices.Sorted(maps.Keys(children))
+}
+
+// generatedTree returns the files of a tree of n source files in several
+// languages, spread over nested directories
+func generatedTree(n int) map[string]string {
+	files := map[string]string{".ctxignore": "*.gen.go\n"}
+	for i := range n {
+		dir := fmt.Sprintf("svc%d/layer%d", i%7, i%3)
+		switch i % 4 {
+		case 0:
+			files[fmt.Sprintf("%s/handler%d.go", dir, i)] = fmt.Sprintf("package layer\n\n// Handler%d serves requests\ntype Handler%d struct {\n\tdb *sql.DB\n}\n\nfunc (h *Handler%d) Serve%d() error { return nil }\n", i, i, i, i)
+		case 1:
+			files[fmt.Sprintf("%s/model%d.py", dir, i)] = fmt.Sprintf("class Model%d:\n    def save_%d(self):\n        pass\n", i, i)
+		case 2:
+			files[fmt.Sprintf("%s/view%d.ts", dir, i)] = fmt.Sprintf("interface View%d { render%d(): void }\nexport function mount%d() {}\n", i, i, i)
+		case 3:
+			files[fmt.Sprintf("%s/table%d.gen.go", dir, i)] = "package layer\n"
+		}
+	}
+	return files
+}
+
+func TestGetContextFileTreeConcurrencyIdentical(t *testing.T) {
+	tempRepo(t, generatedTree(300))
+
+	serial, serialWalked := walkTree(t, parseOptions{concurrency: 1, noCache: true})
+	for _, concurrency := range []int{2, 8, 32} {
+		parallel, walked := walkTree(t, parseOptions{concurrency: concurrency, noCache: true})
+		if !reflect.DeepEqual(parallel, serial) {
+			t.Errorf("concurrency %d: the tree differs from the serial one", concurrency)
+		}
+		// nodes are streamed as they complete, the same ones
+		if !slices.Equal(slices.Sorted(slices.Values(walked)), slices.Sorted(slices.Values(serialWalked))) {
+			t.Errorf("concurrency %d: streamed %d nodes, %d serially", concurrency, len(walked), len(serialWalked))
+		}
+	}
+
+	// the tree isn't trivially equal, files were parsed
+	handler := serial.Children["svc0"].Children["layer0"].Children["handler0.go"]
+	if handler == nil || !slices.Contains(handler.Keywords, "Serve0") {
+		t.Errorf("handler0.go is %+v", handler)
+	}
+}
+
+func BenchmarkGetContextFileTree(b *testing.B) {
+	tempRepo(b, generatedTree(2000))
+	// logging each file would dominate the walk
+	level := zerolog.GlobalLevel()
+	zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	b.Cleanup(func() { zerolog.SetGlobalLevel(level) })
+
+	for _, concurrency := range []int{1, 8} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			for range b.N {
+				walkTree(b, parseOptions{concurrency: concurrency, noCache: true})
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	ctxtelemetry "github.com/cyber-nic/ctx/libs/telemetry"
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	workMaxAttempts = 5
+	workBackoffBase = time.Second
+	workBackoffMax  = 30 * time.Second
+)
+
+var errRateLimited = errors.New("rate limited by provider")
+
+// -on-stale values, what to do with a work target edited while its changes were requested
+const (
+	onStaleRetry = "retry"
+	onStaleSkip  = "skip"
+)
+
+// bytesPerToken is a rough average for code, good enough to catch targets far over a limit
+const bytesPerToken = 4
+
+// estimateTargetTokens estimates the tokens of the work target as presented to
+// the model, line numbers included
+func estimateTargetTokens(content string, format ctxtypes.LineFormat) int {
+	lines := strings.Count(content, "\n") + 1
+	size := len(content) + lines*len(format.Prefix(lines))
+	return size / bytesPerToken
+}
+
+// workResult holds the outcome of a single work request. done is closed once resp or err is set.
+type workResult struct {
+	resp ctxtypes.StepFileWorkResponseSchema
+	err  error
+	done chan struct{}
+}
+
+// runWork sends the work requests using at most concurrency connections and
+// returns one result per request, in request order. The first worker uses *ws,
+// the others dial their own connection to addr, asking for the same features.
+// A worker losing its connection dials a new one, see dialWithBackoff, and
+// sends the request again. The first worker stores its new connection in *ws,
+// which is safe to read once every result is done.
+func runWork(ctx context.Context, ws **websocket.Conn, addr string, features []string, jobs []ctxtypes.CtxRequest, concurrency int, reconnect reconnectOptions) []*workResult {
+	results := make([]*workResult, len(jobs))
+	for i := range results {
+		results[i] = &workResult{done: make(chan struct{})}
+	}
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(jobs) {
+		concurrency = len(jobs)
+	}
+
+	queue := make(chan int, len(jobs))
+	for i := range jobs {
+		queue <- i
+	}
+	close(queue)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		conn := *ws
+		if w > 0 {
+			c, _, err := dial(ctx, addr, features)
+			if err != nil {
+				log.Warn().Err(err).Int("worker", w).Msg("dial, continuing with fewer workers")
+				continue
+			}
+			conn = c
+		}
+
+		wg.Add(1)
+		go func(w int, conn *websocket.Conn) {
+			defer wg.Done()
+			defer func() {
+				if w > 0 {
+					closeConn(conn)
+				}
+			}()
+
+			for i := range queue {
+				res := results[i]
+				res.resp, res.err = requestWorkWithBackoff(ctx, conn, jobs[i])
+				if connectionLost(res.err) {
+					log.Warn().Err(res.err).Int("worker", w).Msg("connection lost, reconnecting")
+					if c, _, err := dialWithBackoff(ctx, addr, features, reconnect); err == nil {
+						closeConn(conn)
+						conn = c
+						if w == 0 {
+							*ws = c
+						}
+						res.resp, res.err = requestWorkWithBackoff(ctx, conn, jobs[i])
+					}
+				}
+				close(res.done)
+			}
+		}(w, conn)
+	}
+
+	return results
+}
+
+// requestWorkWithBackoff retries a work request with exponential backoff while the provider is rate limiting
+func requestWorkWithBackoff(ctx context.Context, conn *websocket.Conn, msg ctxtypes.CtxRequest) (ctxtypes.StepFileWorkResponseSchema, error) {
+	backoff := workBackoffBase
+
+	for attempt := 1; ; attempt++ {
+		resp, err := requestWork(ctx, conn, msg)
+		if !errors.Is(err, errRateLimited) || attempt == workMaxAttempts {
+			return resp, err
+		}
+
+		log.Warn().Int("attempt", attempt).Dur("backoff", backoff).Msg("rate limited, backing off")
+		select {
+		case <-ctx.Done():
+			return resp, ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > workBackoffMax {
+			backoff = workBackoffMax
+		}
+	}
+}
+
+// requestWork sends a single work request and waits for its response
+func requestWork(ctx context.Context, conn *websocket.Conn, msg ctxtypes.CtxRequest) (ctxtypes.StepFileWorkResponseSchema, error) {
+	var workResp ctxtypes.StepFileWorkResponseSchema
+
+	ctx, span := ctxtelemetry.Tracer().Start(ctx, "work "+msg.WorkTarget.Path)
+	defer span.End()
+
+	if err := sendRequest(ctx, conn, msg); err != nil {
+		return workResp, err
+	}
+
+	message, err := readResponse(conn)
+	if err != nil {
+		if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+			return workResp, fmt.Errorf("connection closed by server: %w", err)
+		}
+		return workResp, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if err := json.Unmarshal(message, &workResp); err != nil {
+		return workResp, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if workResp.Status == ctxtypes.StatusRateLimited {
+		return workResp, errRateLimited
+	}
+
+	return workResp, nil
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	"github.com/gorilla/websocket"
+)
+
+// workJobs returns n work requests, targeting f0.go, f1.go...
+func workJobs(n int) []ctxtypes.CtxRequest {
+	jobs := make([]ctxtypes.CtxRequest, n)
+	for i := range jobs {
+		jobs[i] = ctxtypes.CtxRequest{Step: ctxtypes.CtxStepCodeWork, WorkTarget: &ctxtypes.WorkTarget{Path: fmt.Sprintf("f%d.go", i)}}
+	}
+	return jobs
+}
+
+// patchFor is the response of the test servers to a work request, its patch names the target
+func patchFor(req ctxtypes.CtxRequest) ctxtypes.StepFileWorkResponseSchema {
+	return ctxtypes.StepFileWorkResponseSchema{Step: string(req.Step), Status: ctxtypes.StatusOK, Data: ctxtypes.PatchData{Patch: req.WorkTarget.Path}}
+}
+
+func TestRunWorkBoundsConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+
+	srv := newTestServer(t, nil, func(_ int, c *websocket.Conn) {
+		serveRequests(c, func(req ctxtypes.CtxRequest) any {
+			mu.Lock()
+			inFlight++
+			maxInFlight = max(maxInFlight, inFlight)
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+			return patchFor(req)
+		})
+	})
+
+	features := []string{ctxtypes.FeatureKeepalive}
+	ws, _, err := dial(context.Background(), srv.addr, features)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	jobs := workJobs(8)
+	for i, res := range runWork(context.Background(), &ws, srv.addr, features, jobs, 3, reconnectOptions{attempts: 1}) {
+		<-res.done
+		if res.err != nil {
+			t.Fatalf("job %d: %v", i, res.err)
+		}
+		if got := res.resp.Data.Patch; got != jobs[i].WorkTarget.Path {
+			t.Errorf("job %d: got the patch of %s", i, got)
+		}
+	}
+
+	if maxInFlight > 3 {
+		t.Errorf("%d requests in flight, want at most 3", maxInFlight)
+	}
+	conns := srv.connections()
+	if len(conns) != 3 {
+		t.Fatalf("%d connections, want 3", len(conns))
+	}
+	for i, f := range conns {
+		if f != ctxtypes.FeatureKeepalive {
+			t.Errorf("connection %d asked for features %q, want %q", i, f, ctxtypes.FeatureKeepalive)
+		}
+	}
+}
+
+func TestRunWorkReconnectReplacesConnection(t *testing.T) {
+	srv := newTestServer(t, nil, func(n int, c *websocket.Conn) {
+		serveRequests(c, func(req ctxtypes.CtxRequest) any {
+			// the first connection drops on its first request
+			if n == 1 {
+				return nil
+			}
+			return patchFor(req)
+		})
+	})
+
+	features := []string{ctxtypes.FeatureChunkedLoad}
+	ws, _, err := dial(context.Background(), srv.addr, features)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := ws
+
+	results := runWork(context.Background(), &ws, srv.addr, features, workJobs(2), 1, reconnectOptions{attempts: 3, maxDelay: 10 * time.Millisecond})
+	for i, res := range results {
+		<-res.done
+		if res.err != nil {
+			t.Fatalf("job %d: %v", i, res.err)
+		}
+	}
+	defer ws.Close()
+
+	if ws == first {
+		t.Fatal("the caller's connection wasn't replaced after the reconnect")
+	}
+	if conns := srv.connections(); len(conns) != 2 || conns[1] != ctxtypes.FeatureChunkedLoad {
+		t.Errorf("connections asked for %q, want the features on the reconnect", conns)
+	}
+
+	// the replacement is usable by the caller
+	if _, err := requestWork(context.Background(), ws, workJobs(1)[0]); err != nil {
+		t.Errorf("request on the replaced connection: %v", err)
+	}
+}
+
+func TestRequestWorkWithBackoff(t *testing.T) {
+	srv := newTestServer(t, nil, func(_ int, c *websocket.Conn) {
+		attempts := 0
+		serveRequests(c, func(req ctxtypes.CtxRequest) any {
+			attempts++
+			if attempts == 1 {
+				return ctxtypes.StepFileWorkResponseSchema{Step: string(req.Step), Status: ctxtypes.StatusRateLimited}
+			}
+			return patchFor(req)
+		})
+	})
+
+	ws, _, err := dial(context.Background(), srv.addr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	resp, err := requestWorkWithBackoff(context.Background(), ws, workJobs(1)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != ctxtypes.StatusOK {
+		t.Errorf("status %q after the rate limit, want %q", resp.Status, ctxtypes.StatusOK)
+	}
+}
+
+func TestRequestWorkWithBackoffCanceled(t *testing.T) {
+	srv := newTestServer(t, nil, func(_ int, c *websocket.Conn) {
+		serveRequests(c, func(req ctxtypes.CtxRequest) any {
+			return ctxtypes.StepFileWorkResponseSchema{Step: string(req.Step), Status: ctxtypes.StatusRateLimited}
+		})
+	})
+
+	ws, _, err := dial(context.Background(), srv.addr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = requestWorkWithBackoff(ctx, ws, workJobs(1)[0])
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want the context error", err)
+	}
+	if elapsed := time.Since(start); elapsed > workBackoffBase/2 {
+		t.Errorf("returned after %s, the backoff ignored the context", elapsed)
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	ctxtelemetry "github.com/cyber-nic/ctx/libs/telemetry"
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	ctxutils "github.com/cyber-nic/ctx/libs/utils"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+// reconnectBaseDelay is the wait after the first failed dial, doubled after each one
+const reconnectBaseDelay = 500 * time.Millisecond
+
+// reconnectOptions bound the dial retries, see dialWithBackoff
+type reconnectOptions struct {
+	// attempts is the maximum number of dials, 1 disables retries
+	attempts int
+	maxDelay time.Duration
+}
+
+// sendRequest marshals and writes a request, carrying the trace context of ctx.
+// A write error means nothing was delivered, so callers must not go on to wait
+// for a response.
+func sendRequest(ctx context.Context, conn *websocket.Conn, msg ctxtypes.CtxRequest) error {
+	msg.TraceParent = ctxtelemetry.Inject(ctx)
+
+	msgData, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %w", msg.Step, err)
+	}
+
+	if err := conn.WriteMessage(websocket.TextMessage, msgData); err != nil {
+		return fmt.Errorf("failed to send %s request: %w", msg.Step, err)
+	}
+
+	return nil
+}
+
+// dial connects to the server, asking for the protocol features. It returns
+// the connection and the features the server agreed to.
+func dial(ctx context.Context, addr string, features []string) (*websocket.Conn, map[string]bool, error) {
+	_, span := ctxtelemetry.Tracer().Start(ctx, "connect")
+	defer span.End()
+
+	header := http.Header{ctxtypes.FeatureHeader: {strings.Join(features, ",")}}
+	ws, resp, err := websocket.DefaultDialer.Dial(addr, header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accepted := acceptedFeatures(resp)
+	if accepted[ctxtypes.FeatureKeepalive] {
+		if interval, err := time.ParseDuration(resp.Header.Get(ctxtypes.PingIntervalHeader)); err == nil && interval > 0 {
+			ctxutils.KeepAlive(ws, interval)
+			keepaliveWaits.Store(ws, ctxutils.KeepAliveWait(interval))
+		}
+	}
+
+	return ws, accepted, nil
+}
+
+// dialWithBackoff dials until it succeeds, at most opts.attempts times, waiting
+// twice as long after each failure, up to opts.maxDelay
+func dialWithBackoff(ctx context.Context, addr string, features []string, opts reconnectOptions) (*websocket.Conn, map[string]bool, error) {
+	delay := min(reconnectBaseDelay, opts.maxDelay)
+
+	for attempt := 1; ; attempt++ {
+		ws, accepted, err := dial(ctx, addr, features)
+		if err == nil || attempt >= opts.attempts {
+			return ws, accepted, err
+		}
+
+		log.Warn().Err(err).Int("attempt", attempt).Dur("backoff", delay).Msg("dial failed, retrying")
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay = min(delay*2, opts.maxDelay)
+	}
+}
+
+// connectionLost reports whether the error is a failure of the connection
+// itself, rather than of a request, after which the connection can't be used
+func connectionLost(err error) bool {
+	var closeErr *websocket.CloseError
+	var netErr net.Error
+	return errors.As(err, &closeErr) || errors.As(err, &netErr) ||
+		errors.Is(err, websocket.ErrCloseSent) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
+
+// acceptedFeatures returns the protocol features the server agreed to in its upgrade response
+func acceptedFeatures(resp *http.Response) map[string]bool {
+	accepted := map[string]bool{}
+	for _, value := range resp.Header.Values(ctxtypes.FeatureHeader) {
+		for _, feature := range strings.Split(value, ",") {
+			accepted[strings.TrimSpace(feature)] = true
+		}
+	}
+	return accepted
+}
+
+// keepaliveWaits holds the read timeout of each keepalive connection, see
+// readResponse. Entries are deleted by closeConn.
+var keepaliveWaits sync.Map
+
+// closeConn closes the connection and forgets its keepalive read timeout
+func closeConn(conn *websocket.Conn) error {
+	keepaliveWaits.Delete(conn)
+	return conn.Close()
+}
+
+// statusMu serializes status line rendering across concurrent work requests
+var statusMu sync.Mutex
+
+// readResponse reads the next step response, rendering any status messages
+// received before it as a live status line. The work output streamed before
+// the response is written to stdout as it is received. On a keepalive
+// connection, the read times out when the server goes silent, missing its pings.
+func readResponse(conn *websocket.Conn) ([]byte, error) {
+	streamed := 0
+
+	for {
+		if wait, ok := keepaliveWaits.Load(conn); ok {
+			conn.SetReadDeadline(time.Now().Add(wait.(time.Duration)))
+		}
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			clearStatus()
+			return nil, err
+		}
+
+		var status ctxtypes.StepStatusResponseSchema
+		if err := json.Unmarshal(message, &status); err != nil {
+			clearStatus()
+			return message, nil
+		}
+
+		switch ctxtypes.CtxStep(status.Step) {
+		case ctxtypes.CtxStepStatus:
+			// the status line would overwrite the streamed output
+			if streamed == 0 {
+				renderStatus(status.Data)
+			}
+		case ctxtypes.CtxStepWorkChunk:
+			var chunk ctxtypes.StepWorkChunkResponseSchema
+			if err := json.Unmarshal(message, &chunk); err != nil {
+				log.Warn().Err(err).Msg("invalid work chunk")
+				continue
+			}
+			if streamed == 0 {
+				clearStatus()
+			}
+			streamed += len(chunk.Chunk)
+			writeStreamed(chunk.Chunk)
+		default:
+			clearStatus()
+			if streamed > 0 {
+				writeStreamed("\n")
+				log.Debug().Int("bytes", streamed).Msg("streamed work output")
+			}
+			return message, nil
+		}
+	}
+}
+
+// renderStatus overwrites the current terminal line on stderr with the status
+func renderStatus(s ctxtypes.StepStatusData) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+
+	if s.Percent > 0 {
+		fmt.Fprintf(os.Stderr, "\r\033[K%s... %d%%", s.Phase, s.Percent)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\r\033[K%s...", s.Phase)
+}
+
+// writeStreamed writes a piece of the work output to stdout as it is generated
+func writeStreamed(chunk string) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+
+	os.Stdout.WriteString(chunk)
+}
+
+// renderUpload overwrites the current terminal line on stderr with the share of bytes sent
+func renderUpload(phase string, sent, total int) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+
+	fmt.Fprintf(os.Stderr, "\r\033[K%s: %d%%/%s", phase, sent*100/total, formatSize(total))
+}
+
+// formatSize renders a byte count in a short human readable form, e.g. 2.3MB
+func formatSize(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%dB", n)
+	}
+}
+
+func clearStatus() {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+
+	fmt.Fprint(os.Stderr, "\r\033[K")
+}
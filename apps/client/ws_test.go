@@ -0,0 +1,387 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	ctxutils "github.com/cyber-nic/ctx/libs/utils"
+	"github.com/gorilla/websocket"
+)
+
+// testServer is a websocket server handing each connection to handle, along
+// with its number, starting at 1
+type testServer struct {
+	*httptest.Server
+	addr string
+
+	mu sync.Mutex
+	// features are the X-Ctx-Features asked for by each connection
+	features []string
+}
+
+// newTestServer starts a server that accepts every feature asked for, setting
+// the extra upgrade response headers
+func newTestServer(t *testing.T, header http.Header, handle func(n int, c *websocket.Conn)) *testServer {
+	t.Helper()
+
+	s := &testServer{}
+	upgrader := websocket.Upgrader{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := header.Clone()
+		if h == nil {
+			h = http.Header{}
+		}
+		requested := r.Header.Get(ctxtypes.FeatureHeader)
+		h.Set(ctxtypes.FeatureHeader, requested)
+
+		c, err := upgrader.Upgrade(w, r, h)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		defer c.Close()
+
+		s.mu.Lock()
+		s.features = append(s.features, requested)
+		n := len(s.features)
+		s.mu.Unlock()
+
+		handle(n, c)
+	}))
+	s.addr = "ws" + strings.TrimPrefix(s.URL, "http")
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// connections returns the features asked for by each connection so far
+func (s *testServer) connections() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.features...)
+}
+
+// serveRequests answers each request read from the connection with respond,
+// until respond returns nil or the connection fails
+func serveRequests(c *websocket.Conn, respond func(req ctxtypes.CtxRequest) any) {
+	for {
+		var req ctxtypes.CtxRequest
+		if err := c.ReadJSON(&req); err != nil {
+			return
+		}
+		resp := respond(req)
+		if resp == nil {
+			return
+		}
+		if err := c.WriteJSON(resp); err != nil {
+			return
+		}
+	}
+}
+
+func TestRequestWorkWriteFails(t *testing.T) {
+	received := make(chan ctxtypes.CtxRequest, 1)
+	srv := newTestServer(t, nil, func(_ int, c *websocket.Conn) {
+		// nothing is answered, a read after a failed write would wait forever
+		var req ctxtypes.CtxRequest
+		if err := c.ReadJSON(&req); err == nil {
+			received <- req
+		}
+	})
+
+	ws, _, err := dial(context.Background(), srv.addr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	ws.SetWriteDeadline(time.Now().Add(-time.Second))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := requestWork(context.Background(), ws, workJobs(1)[0])
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "failed to send work request") {
+			t.Errorf("got %v, want the write error", err)
+		}
+		if !connectionLost(err) {
+			t.Errorf("%v isn't a lost connection, it won't be retried", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("requestWork waited for a response to a request that wasn't sent")
+	}
+	select {
+	case req := <-received:
+		t.Errorf("the server received %+v", req)
+	default:
+	}
+}
+
+func TestRunWorkResendsAfterWriteFails(t *testing.T) {
+	srv := newTestServer(t, nil, func(_ int, c *websocket.Conn) {
+		serveRequests(c, func(req ctxtypes.CtxRequest) any { return patchFor(req) })
+	})
+
+	ws, _, err := dial(context.Background(), srv.addr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws.SetWriteDeadline(time.Now().Add(-time.Second))
+
+	jobs := workJobs(1)
+	res := runWork(context.Background(), &ws, srv.addr, nil, jobs, 1, reconnectOptions{attempts: 2, maxDelay: 10 * time.Millisecond})[0]
+	<-res.done
+	defer ws.Close()
+
+	if res.err != nil {
+		t.Fatalf("got %v, want the request sent again on a new connection", res.err)
+	}
+	if got := res.resp.Data.Patch; got != jobs[0].WorkTarget.Path {
+		t.Errorf("got the patch of %s", got)
+	}
+	if conns := srv.connections(); len(conns) != 2 {
+		t.Errorf("%d connections, want a reconnect", len(conns))
+	}
+}
+
+// flakyServer drops the connections it gets before accepting them, from the
+// drops+1th one on, answering each work request with its patch
+type flakyServer struct {
+	*testServer
+	drops int
+
+	mu       sync.Mutex
+	attempts int
+}
+
+func newFlakyServer(t *testing.T, drops int) *flakyServer {
+	t.Helper()
+	s := &flakyServer{drops: drops}
+	s.testServer = newTestServer(t, nil, func(_ int, c *websocket.Conn) {
+		serveRequests(c, func(req ctxtypes.CtxRequest) any { return patchFor(req) })
+	})
+
+	// the connection is closed before the upgrade, as by a server that is restarting
+	accept := s.Config.Handler
+	s.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.attempts++
+		drop := s.attempts <= s.drops
+		s.mu.Unlock()
+		if drop {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		accept.ServeHTTP(w, r)
+	})
+	return s
+}
+
+// dials returns the number of connections attempted so far
+func (s *flakyServer) dials() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.attempts
+}
+
+// dropAll drops every later connection
+func (s *flakyServer) dropAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.drops = math.MaxInt
+}
+
+func TestDialWithBackoff(t *testing.T) {
+	srv := newFlakyServer(t, 2)
+
+	start := time.Now()
+	ws, accepted, err := dialWithBackoff(context.Background(), srv.addr, []string{ctxtypes.FeatureChunkedLoad}, reconnectOptions{attempts: 3, maxDelay: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("got %v, want a connection on the third dial", err)
+	}
+	defer ws.Close()
+
+	if srv.dials() != 3 {
+		t.Errorf("%d dials, want 3", srv.dials())
+	}
+	// waits of 20ms, capped from the 500ms base
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond || elapsed > reconnectBaseDelay {
+		t.Errorf("connected after %s", elapsed)
+	}
+	if !accepted[ctxtypes.FeatureChunkedLoad] {
+		t.Errorf("got features %v after the reconnect", accepted)
+	}
+	if _, err := requestWork(context.Background(), ws, workJobs(1)[0]); err != nil {
+		t.Errorf("request on the new connection: %v", err)
+	}
+}
+
+func TestDialWithBackoffGivesUp(t *testing.T) {
+	srv := newFlakyServer(t, 3)
+
+	if _, _, err := dialWithBackoff(context.Background(), srv.addr, nil, reconnectOptions{attempts: 3, maxDelay: time.Millisecond}); err == nil {
+		t.Fatal("connected to a server dropping every dial")
+	}
+	if srv.dials() != 3 {
+		t.Errorf("%d dials, want 3", srv.dials())
+	}
+
+	// the wait is cut short by the context
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	srv.dropAll()
+	start := time.Now()
+	if _, _, err := dialWithBackoff(ctx, srv.addr, nil, reconnectOptions{attempts: 10, maxDelay: time.Minute}); err != context.DeadlineExceeded {
+		t.Errorf("got %v, want the context error", err)
+	}
+	if elapsed := time.Since(start); elapsed > reconnectBaseDelay {
+		t.Errorf("returned after %s, the backoff ignored the context", elapsed)
+	}
+}
+
+func TestRunWorkReconnectsToRestartedServer(t *testing.T) {
+	// the first connection drops its request, the server is down for the next dial
+	var mu sync.Mutex
+	restarting := false
+	srv := newTestServer(t, nil, func(n int, c *websocket.Conn) {
+		serveRequests(c, func(req ctxtypes.CtxRequest) any {
+			if n == 1 {
+				mu.Lock()
+				restarting = true
+				mu.Unlock()
+				return nil
+			}
+			return patchFor(req)
+		})
+	})
+	accept := srv.Config.Handler
+	redials := 0
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		drop := restarting && redials == 0
+		if restarting {
+			redials++
+		}
+		mu.Unlock()
+		if drop {
+			http.Error(w, "restarting", http.StatusServiceUnavailable)
+			return
+		}
+		accept.ServeHTTP(w, r)
+	})
+
+	ws, _, err := dial(context.Background(), srv.addr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs := workJobs(1)
+	res := runWork(context.Background(), &ws, srv.addr, nil, jobs, 1, reconnectOptions{attempts: 3, maxDelay: 10 * time.Millisecond})[0]
+	<-res.done
+	defer ws.Close()
+
+	if res.err != nil {
+		t.Fatalf("got %v, want the request sent again once the server is back", res.err)
+	}
+	if got := res.resp.Data.Patch; got != jobs[0].WorkTarget.Path {
+		t.Errorf("got the patch of %s", got)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if redials != 2 {
+		t.Errorf("%d dials after the drop, want 2", redials)
+	}
+}
+
+func TestReadResponseKeepalive(t *testing.T) {
+	const interval = 20 * time.Millisecond
+	header := http.Header{ctxtypes.PingIntervalHeader: {interval.String()}}
+
+	for _, ping := range []bool{true, false} {
+		// the response takes well past the wait after which a silent server is deemed gone
+		srv := newTestServer(t, header, func(_ int, c *websocket.Conn) {
+			if ping {
+				ctxutils.KeepAlive(c, interval)
+			}
+			serveRequests(c, func(req ctxtypes.CtxRequest) any {
+				time.Sleep(10 * ctxutils.KeepAliveWait(interval))
+				return patchFor(req)
+			})
+		})
+
+		ws, _, err := dial(context.Background(), srv.addr, []string{ctxtypes.FeatureKeepalive})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ws.Close()
+
+		_, err = requestWork(context.Background(), ws, workJobs(1)[0])
+		if ping && err != nil {
+			t.Errorf("got %v waiting on a server that pings", err)
+		}
+		var netErr net.Error
+		if !ping && !(errors.As(err, &netErr) && netErr.Timeout()) {
+			t.Errorf("got %v waiting on a silent server, want a timeout", err)
+		}
+	}
+}
+
+// keepaliveConns returns the number of connections with a keepalive read timeout
+func keepaliveConns() int {
+	n := 0
+	keepaliveWaits.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+func TestCloseConnForgetsKeepalive(t *testing.T) {
+	before := keepaliveConns()
+
+	// the first connection drops its request, its worker reconnects
+	header := http.Header{ctxtypes.PingIntervalHeader: {time.Second.String()}}
+	srv := newTestServer(t, header, func(n int, c *websocket.Conn) {
+		serveRequests(c, func(req ctxtypes.CtxRequest) any {
+			if n == 1 {
+				return nil
+			}
+			return patchFor(req)
+		})
+	})
+
+	features := []string{ctxtypes.FeatureKeepalive}
+	ws, _, err := dial(context.Background(), srv.addr, features)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, res := range runWork(context.Background(), &ws, srv.addr, features, workJobs(4), 2, reconnectOptions{attempts: 1}) {
+		<-res.done
+		if res.err != nil {
+			t.Fatal(res.err)
+		}
+	}
+
+	// the replaced connection and the extra worker's are forgotten, the current one is kept
+	if got := keepaliveConns() - before; got != 1 {
+		t.Errorf("%d connections remembered, want the current one", got)
+	}
+	closeConn(ws)
+	if got := keepaliveConns() - before; got != 0 {
+		t.Errorf("%d connections remembered once closed", got)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	ctxtelemetry "github.com/cyber-nic/ctx/libs/telemetry"
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	"github.com/tmc/langchaingo/llms"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// tokenBudget accumulates the tokens used by each client, against a limit
+// shared by all clients. A zero limit disables the budget.
+type tokenBudget struct {
+	mu    sync.Mutex
+	limit int
+	used  map[string]int
+}
+
+func newTokenBudget(limit int) *tokenBudget {
+	return &tokenBudget{limit: limit, used: map[string]int{}}
+}
+
+// exceeded reports whether the client has used up its budget
+func (b *tokenBudget) exceeded(clientID string) bool {
+	if b.limit <= 0 {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.used[clientID] >= b.limit
+}
+
+// add records the tokens used by the client
+func (b *tokenBudget) add(clientID string, tokens int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.used[clientID] += tokens
+}
+
+// status returns the client's usage against the limit, nil when the budget is disabled
+func (b *tokenBudget) status(clientID string) *ctxtypes.TokenBudget {
+	if b.limit <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return &ctxtypes.TokenBudget{Used: b.used[clientID], Limit: b.limit}
+}
+
+// responseTokens returns the total tokens of a response, as reported by the provider.
+// Usage is reported per response, every choice carries the same figures.
+func responseTokens(resp *llms.ContentResponse) int {
+	if resp == nil || len(resp.Choices) == 0 {
+		return 0
+	}
+	info := resp.Choices[0].GenerationInfo
+
+	// googleai and openai report the total
+	for _, key := range []string{"total_tokens", "TotalTokens"} {
+		if n, ok := tokenCount(info[key]); ok {
+			return n
+		}
+	}
+
+	// anthropic only reports each side
+	in, _ := tokenCount(info["InputTokens"])
+	out, _ := tokenCount(info["OutputTokens"])
+	return in + out
+}
+
+// tokenCount converts a token count of the generation info, whose type depends on the provider
+func tokenCount(v any) (int, bool) {
+	switch n := v.(type) {
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case int:
+		return n, true
+	}
+	return 0, false
+}
+
+// meteredModel is the model as used on behalf of a client: each generation is
+// traced and its tokens are charged to the client's budget
+type meteredModel struct {
+	llm      llms.Model
+	budget   *tokenBudget
+	clientID string
+}
+
+func (m meteredModel) GenerateContent(ctx context.Context, messages []llms.MessageContent, opts ...llms.CallOption) (*llms.ContentResponse, error) {
+	ctx, span := ctxtelemetry.Tracer().Start(ctx, "GenerateContent")
+	defer span.End()
+
+	resp, err := m.llm.GenerateContent(ctx, messages, opts...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "generation failed")
+	}
+
+	m.budget.add(m.clientID, responseTokens(resp))
+	return resp, err
+}
+
+func (m meteredModel) Call(ctx context.Context, prompt string, opts ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, opts...)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	"github.com/gorilla/websocket"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/anthropic"
+	"github.com/tmc/langchaingo/llms/openai"
+)
+
+func TestResponseTokens(t *testing.T) {
+	tests := []struct {
+		name string
+		info map[string]any
+		want int
+	}{
+		// as set by llms/googleai
+		{"googleai", map[string]any{"input_tokens": int32(7), "output_tokens": int32(5), "total_tokens": int32(12)}, 12},
+		// as set by llms/openai
+		{"openai", map[string]any{"PromptTokens": 7, "CompletionTokens": 5, "TotalTokens": 12}, 12},
+		// as set by llms/anthropic
+		{"anthropic", map[string]any{"InputTokens": 7, "OutputTokens": 5}, 12},
+		{"no usage", map[string]any{}, 0},
+	}
+	for _, tt := range tests {
+		resp := &llms.ContentResponse{Choices: []*llms.ContentChoice{{GenerationInfo: tt.info}}}
+		if got := responseTokens(resp); got != tt.want {
+			t.Errorf("%s: got %d tokens, want %d", tt.name, got, tt.want)
+		}
+	}
+	if got := responseTokens(nil); got != 0 {
+		t.Errorf("nil response: got %d tokens", got)
+	}
+}
+
+// TestResponseTokensProviders reads the usage of responses parsed by the
+// provider clients themselves, served by a fake API
+func TestResponseTokensProviders(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		llm  func(url string) (llms.Model, error)
+	}{
+		{
+			name: "openai",
+			body: `{"id":"c","object":"chat.completion","created":1,"model":"gpt-4o",
+				"choices":[{"index":0,"message":{"role":"assistant","content":"{}"},"finish_reason":"stop"}],
+				"usage":{"prompt_tokens":7,"completion_tokens":5,"total_tokens":12}}`,
+			llm: func(url string) (llms.Model, error) {
+				return openai.New(openai.WithToken("key"), openai.WithBaseURL(url))
+			},
+		},
+		{
+			name: "anthropic",
+			body: `{"id":"m","type":"message","role":"assistant","model":"claude",
+				"content":[{"type":"text","text":"{}"}],"stop_reason":"end_turn",
+				"usage":{"input_tokens":7,"output_tokens":5}}`,
+			llm: func(url string) (llms.Model, error) {
+				return anthropic.New(anthropic.WithToken("key"), anthropic.WithBaseURL(url))
+			},
+		},
+	}
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(tt.body))
+		}))
+		defer srv.Close()
+
+		llm, err := tt.llm(srv.URL)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		budget := newTokenBudget(100)
+		metered := meteredModel{llm: llm, budget: budget, clientID: "client"}
+		if _, err := metered.GenerateContent(context.Background(), []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")}); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if used := budget.status("client").Used; used != 12 {
+			t.Errorf("%s: charged %d tokens, want 12", tt.name, used)
+		}
+	}
+}
+
+func TestHandlerBudgetExceeded(t *testing.T) {
+	svc := NewCodeContextService(streamingModel{content: `{"summary":"one step","steps":[]}`}, "test-model", "", nil, 0, 10, 0, 1, 1<<20)
+	svc.(*codeContextService).budget.add("client", 12)
+	srv := httptest.NewServer(http.HandlerFunc(svc.Handler(context.Background())))
+	t.Cleanup(srv.Close)
+
+	c, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	if err := c.WriteJSON(ctxtypes.CtxRequest{ClientID: "client", Step: ctxtypes.CtxStepPlan, UserPrompt: "plan it"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the client is turned away before being told the plan is under way
+	var resp ctxtypes.StepStatusResponseSchema
+	err = c.ReadJSON(&resp)
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) {
+		t.Fatalf("got %+v, %v, want the close frame first", resp, err)
+	}
+	if closeErr.Code != websocket.ClosePolicyViolation || closeErr.Text != "token budget exceeded: 12 of 10 tokens used" {
+		t.Errorf("got %v", closeErr)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+// contentCache stores file contents keyed by their content hash. It is shared
+// by all connections so that unchanged files are only uploaded once. The least
+// recently used contents are evicted once the cache holds more than maxBytes.
+type contentCache struct {
+	mu       sync.Mutex
+	maxBytes int
+	size     int
+	// lru holds the cached blobs, most recently used first
+	lru   *list.List
+	blobs map[string]*list.Element
+}
+
+// cachedBlob is an element of contentCache.lru
+type cachedBlob struct {
+	hash    string
+	content string
+}
+
+func newContentCache(maxBytes int) *contentCache {
+	return &contentCache{maxBytes: maxBytes, lru: list.New(), blobs: map[string]*list.Element{}}
+}
+
+// missing returns the hashes of the manifest that are not cached, without duplicates
+func (c *contentCache) missing(manifest map[string]string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := map[string]bool{}
+	missing := []string{}
+	for _, hash := range manifest {
+		if _, ok := c.blobs[hash]; ok || seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		missing = append(missing, hash)
+	}
+	return missing
+}
+
+// store caches the blobs whose content matches their hash and returns how many
+// were stored. A blob larger than the whole cache isn't stored.
+func (c *contentCache) store(blobs map[string]string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stored := 0
+	for hash, content := range blobs {
+		if ctxtypes.ContentHash(content) != hash || len(content) > c.maxBytes {
+			continue
+		}
+		if e, ok := c.blobs[hash]; ok {
+			c.lru.MoveToFront(e)
+		} else {
+			c.blobs[hash] = c.lru.PushFront(&cachedBlob{hash: hash, content: content})
+			c.size += len(content)
+		}
+		stored++
+	}
+	c.evict()
+	return stored
+}
+
+// evict drops the least recently used blobs until the cache fits in maxBytes
+func (c *contentCache) evict() {
+	for c.size > c.maxBytes {
+		e := c.lru.Back()
+		b := c.lru.Remove(e).(*cachedBlob)
+		delete(c.blobs, b.hash)
+		c.size -= len(b.content)
+	}
+}
+
+// resolve fills in the contents of files referenced by hash and returns the paths that could not be resolved
+func (c *contentCache) resolve(appCtx *ctxtypes.ApplicationContext) []string {
+	if len(appCtx.FileHashes) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if appCtx.FileContents == nil {
+		appCtx.FileContents = map[string]string{}
+	}
+
+	unresolved := []string{}
+	for path, hash := range appCtx.FileHashes {
+		e, ok := c.blobs[hash]
+		if !ok {
+			unresolved = append(unresolved, path)
+			continue
+		}
+		c.lru.MoveToFront(e)
+		appCtx.FileContents[path] = e.Value.(*cachedBlob).content
+	}
+	appCtx.FileHashes = nil
+
+	return unresolved
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+// blobsOf returns the blobs of the contents, keyed by hash
+func blobsOf(contents ...string) map[string]string {
+	blobs := map[string]string{}
+	for _, c := range contents {
+		blobs[ctxtypes.ContentHash(c)] = c
+	}
+	return blobs
+}
+
+func TestContentCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	a, b, c := strings.Repeat("a", 40), strings.Repeat("b", 40), strings.Repeat("c", 40)
+	cache := newContentCache(100)
+
+	cache.store(blobsOf(a))
+	cache.store(blobsOf(b))
+
+	// using a makes b the least recently used
+	ctx := ctxtypes.ApplicationContext{FileHashes: map[string]string{"a.txt": ctxtypes.ContentHash(a)}}
+	if unresolved := cache.resolve(&ctx); len(unresolved) > 0 {
+		t.Fatalf("unresolved %v", unresolved)
+	}
+
+	cache.store(blobsOf(c))
+
+	missing := cache.missing(map[string]string{"a.txt": ctxtypes.ContentHash(a), "b.txt": ctxtypes.ContentHash(b), "c.txt": ctxtypes.ContentHash(c)})
+	if len(missing) != 1 || missing[0] != ctxtypes.ContentHash(b) {
+		t.Errorf("missing %v, want only b evicted", missing)
+	}
+	if cache.size != 80 {
+		t.Errorf("size %d, want 80", cache.size)
+	}
+}
+
+func TestContentCacheStore(t *testing.T) {
+	cache := newContentCache(10)
+
+	tests := []struct {
+		name  string
+		blobs map[string]string
+		want  int
+	}{
+		{"fits", blobsOf("small"), 1},
+		{"already cached", blobsOf("small"), 1},
+		{"larger than the cache", blobsOf("far too large for the cache"), 0},
+		{"hash mismatch", map[string]string{"not-the-hash": "x"}, 0},
+	}
+	for _, tt := range tests {
+		if got := cache.store(tt.blobs); got != tt.want {
+			t.Errorf("%s: stored %d, want %d", tt.name, got, tt.want)
+		}
+	}
+	if cache.size != len("small") || cache.lru.Len() != 1 {
+		t.Errorf("size %d with %d blobs, want the small blob only", cache.size, cache.lru.Len())
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+)
+
+// contextChunks reassembles a context sent in chunks over a connection
+type contextChunks struct {
+	data     strings.Builder
+	received int
+	total    int
+}
+
+// add appends the chunk if it is the next one expected, a first chunk restarts
+// the context. Chunks out of order are ignored, the acknowledgement tells the
+// client where to resume. The context is returned once the last chunk is received.
+func (b *contextChunks) add(chunk ctxtypes.ContextChunk) (*ctxtypes.ApplicationContext, error) {
+	if chunk.Index == 0 {
+		b.data.Reset()
+		b.received = 0
+		b.total = chunk.Total
+	}
+
+	if chunk.Index != b.received || chunk.Total != b.total {
+		return nil, nil
+	}
+
+	b.data.WriteString(chunk.Data)
+	b.received++
+	if b.received < b.total {
+		return nil, nil
+	}
+
+	var appCtx ctxtypes.ApplicationContext
+	err := json.Unmarshal([]byte(b.data.String()), &appCtx)
+	b.data.Reset()
+	if err != nil {
+		return nil, fmt.Errorf("invalid chunked context: %w", err)
+	}
+
+	return &appCtx, nil
+}
+
+// loadChunk acknowledges a context chunk. It returns the context once complete,
+// to be handled as a load request.
+func loadChunk(c *websocket.Conn, l zerolog.Logger, chunks *contextChunks, req ctxtypes.CtxRequest) (ctxtypes.ApplicationContext, bool) {
+	if req.Chunk == nil {
+		l.Warn().Msg("load-chunk request without a chunk")
+		return ctxtypes.ApplicationContext{}, false
+	}
+
+	appCtx, err := chunks.add(*req.Chunk)
+	if err != nil {
+		l.Err(err).Msg("failed to assemble context")
+		wsErr := websocket.FormatCloseMessage(websocket.CloseInvalidFramePayloadData, "invalid context chunk")
+		c.WriteMessage(websocket.CloseMessage, wsErr)
+		return ctxtypes.ApplicationContext{}, false
+	}
+
+	l.Debug().Int("index", req.Chunk.Index).Int("received", chunks.received).Int("total", chunks.total).Msg("context chunk")
+	writeJSON(c, ctxtypes.StepLoadChunkResponseSchema{
+		Timestamp: time.Now().Format(time.RFC3339),
+		Step:      string(req.Step),
+		Status:    ctxtypes.StatusOK,
+		Received:  chunks.received,
+	})
+
+	if appCtx == nil {
+		return ctxtypes.ApplicationContext{}, false
+	}
+	return *appCtx, true
+}
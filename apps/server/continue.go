@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// maxContinuations caps the follow-up generations used to complete a truncated choice
+const maxContinuations = 3
+
+const continuePrompt = "Your previous response was cut off. Continue exactly where it stopped, without repeating any of it and without any preamble, so that appending your response to the previous one yields the complete response."
+
+// completeTruncated completes, in place, the choices of resp that stopped at
+// the max tokens limit. Each is continued with follow-up generations until the
+// model stops on its own or maxContinuations is reached; a choice that is
+// still truncated then keeps its max tokens stop reason.
+func completeTruncated(ctx context.Context, llm llms.Model, l zerolog.Logger, content []llms.MessageContent, resp *llms.ContentResponse, opts ...llms.CallOption) {
+	if resp == nil {
+		return
+	}
+
+	for i, choice := range resp.Choices {
+		for n := 1; n <= maxContinuations && isMaxTokensStop(choice); n++ {
+			l.Debug().Int("choice", i).Int("continuation", n).Int("len", len(choice.Content)).Msg("continuing truncated ai response")
+
+			messages := append(append([]llms.MessageContent{}, content...),
+				llms.TextParts(llms.ChatMessageTypeAI, choice.Content),
+				llms.TextParts(llms.ChatMessageTypeHuman, continuePrompt),
+			)
+
+			more, err := llm.GenerateContent(ctx, messages, opts...)
+			if err != nil {
+				l.Warn().Err(err).Int("choice", i).Msg("ai failed to continue truncated response")
+				break
+			}
+			if len(more.Choices) == 0 {
+				break
+			}
+
+			choice.Content += more.Choices[0].Content
+			choice.StopReason = more.Choices[0].StopReason
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// truncatingModel returns its parts in turn, each cut at the max tokens limit
+// but the last, then fails
+type truncatingModel struct {
+	mu    sync.Mutex
+	parts []string
+	// calls holds the messages of each generation
+	calls [][]llms.MessageContent
+}
+
+func (m *truncatingModel) GenerateContent(_ context.Context, messages []llms.MessageContent, _ ...llms.CallOption) (*llms.ContentResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := len(m.calls)
+	m.calls = append(m.calls, messages)
+	if n >= len(m.parts) {
+		return nil, errors.New("no more parts")
+	}
+	stop := "length"
+	if n == len(m.parts)-1 {
+		stop = "stop"
+	}
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: m.parts[n], StopReason: stop}}}, nil
+}
+
+func (m *truncatingModel) Call(ctx context.Context, prompt string, opts ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, opts...)
+}
+
+func TestCompleteTruncated(t *testing.T) {
+	content := []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "plan it")}
+
+	tests := []struct {
+		name string
+		// first is the truncated response, parts its continuations
+		first    *llms.ContentChoice
+		parts    []string
+		want     string
+		wantStop string
+		// continuations is the number of follow-up generations
+		continuations int
+	}{
+		{"complete", &llms.ContentChoice{Content: `{"summary":"s"}`, StopReason: "stop"}, nil, `{"summary":"s"}`, "stop", 0},
+		{"continued", &llms.ContentChoice{Content: `{"summ`, StopReason: "length"}, []string{`ary":"s`, `"}`}, `{"summary":"s"}`, "stop", 2},
+		{"anthropic", &llms.ContentChoice{Content: `{"summ`, StopReason: "max_tokens"}, []string{`ary":"s"}`}, `{"summary":"s"}`, "stop", 1},
+		{"capped", &llms.ContentChoice{Content: "a", StopReason: "length"}, []string{"b", "c", "d", "e"}, "abcd", "length", maxContinuations},
+		// the partial response is kept as is
+		{"failed", &llms.ContentChoice{Content: `{"summ`, StopReason: "length"}, nil, `{"summ`, "length", 1},
+	}
+	for _, tt := range tests {
+		m := &truncatingModel{parts: tt.parts}
+		resp := &llms.ContentResponse{Choices: []*llms.ContentChoice{tt.first}}
+		first := tt.first.Content
+
+		completeTruncated(context.Background(), m, zerolog.Nop(), content, resp)
+
+		if got := resp.Choices[0]; got.Content != tt.want || got.StopReason != tt.wantStop {
+			t.Errorf("%s: got %q stopped by %q, want %q stopped by %q", tt.name, got.Content, got.StopReason, tt.want, tt.wantStop)
+		}
+		if len(m.calls) != tt.continuations {
+			t.Errorf("%s: %d continuations, want %d", tt.name, len(m.calls), tt.continuations)
+		}
+
+		// each continuation gets the prompt, the response so far and the request to continue
+		for i, messages := range m.calls {
+			if len(messages) != len(content)+2 {
+				t.Fatalf("%s: continuation %d has %d messages", tt.name, i+1, len(messages))
+			}
+			partial, ask := messages[len(content)], messages[len(content)+1]
+			if partial.Role != llms.ChatMessageTypeAI || ask.Role != llms.ChatMessageTypeHuman || ask.Parts[0] != llms.TextPart(continuePrompt) {
+				t.Errorf("%s: continuation %d got %+v", tt.name, i+1, messages)
+			}
+			if text, want := partial.Parts[0].(llms.TextContent).Text, first+strings.Join(tt.parts[:i], ""); text != want {
+				t.Errorf("%s: continuation %d continues %q, want %q", tt.name, i+1, text, want)
+			}
+		}
+	}
+}
+
+func TestHandlerContinuesTruncatedPlan(t *testing.T) {
+	m := &truncatingModel{parts: []string{`{"summary":"add a`, ` cache","steps":[{"description":"store","files":["cache.go"]}]}`}}
+	addr := newTestService(t, m)
+
+	c, _, err := websocket.DefaultDialer.Dial(addr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	if err := c.WriteJSON(ctxtypes.CtxRequest{ClientID: "client", Step: ctxtypes.CtxStepPlan, UserPrompt: "add a cache"}); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		var resp ctxtypes.StepPlanResponseSchema
+		if err := c.ReadJSON(&resp); err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if resp.Step != string(ctxtypes.CtxStepPlan) {
+			continue
+		}
+		if resp.Data.Summary != "add a cache" || len(resp.Data.Steps) != 1 {
+			t.Errorf("got plan %+v", resp.Data)
+		}
+		break
+	}
+	if len(m.calls) != 2 {
+		t.Errorf("%d generations, want the truncated one and its continuation", len(m.calls))
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/rs/zerolog"
+)
+
+var unsafeFileCharsRegex = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// debugDumpQueueSize is the number of dumps waiting to be written before new ones are dropped
+const debugDumpQueueSize = 16
+
+// debugDump is a context waiting to be written
+type debugDump struct {
+	l        zerolog.Logger
+	clientID string
+	data     []byte
+}
+
+// debugDumper writes the dumps of all connections from a single goroutine, in
+// the order they are received, so that concurrent preloads of a client never
+// race on its dump file. A nil debugDumper is disabled.
+type debugDumper struct {
+	dir   string
+	queue chan debugDump
+}
+
+// newDebugDumper starts the writer of the dumps to dir. It returns nil, disabling dumps, when dir is empty.
+func newDebugDumper(dir string) *debugDumper {
+	if dir == "" {
+		return nil
+	}
+
+	d := &debugDumper{dir: dir, queue: make(chan debugDump, debugDumpQueueSize)}
+	go func() {
+		for job := range d.queue {
+			if err := writeDebugDump(d.dir, job.clientID, job.data); err != nil {
+				job.l.Err(err).Msg("Failed to write debug context dump")
+			}
+		}
+	}()
+
+	return d
+}
+
+// dump queues the client's context to be written without blocking the request. It is dropped when the queue is full.
+func (d *debugDumper) dump(l zerolog.Logger, clientID string, data []byte) {
+	if d == nil {
+		return
+	}
+
+	select {
+	case d.queue <- debugDump{l: l, clientID: clientID, data: data}:
+	default:
+		l.Warn().Msg("Debug dump queue full, dropping context dump")
+	}
+}
+
+// debugDumpPath returns the per-client dump file path so concurrent clients never share a file
+func debugDumpPath(dir, clientID string) string {
+	id := unsafeFileCharsRegex.ReplaceAllString(clientID, "-")
+	if id == "" {
+		id = "anonymous"
+	}
+	return filepath.Join(dir, fmt.Sprintf("%s.%s", id, debugCodeContextFile))
+}
+
+// writeDebugDump atomically replaces the client's dump file by writing to a
+// temporary file in the same directory and renaming it over the target.
+func writeDebugDump(dir, clientID string, data []byte) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create dump directory: %w", err)
+	}
+
+	target := debugDumpPath(dir, clientID)
+
+	f, err := os.CreateTemp(dir, filepath.Base(target)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+
+	if err := os.Rename(f.Name(), target); err != nil {
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+)
+
+// waitForDump waits for the client's dump to hold want, checking on the way
+// that every version read is one of the dumps sent
+func waitForDump(t *testing.T, dir, clientID, want string, sent map[string]bool) {
+	t.Helper()
+	path := debugDumpPath(dir, clientID)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			if !sent[string(data)] {
+				t.Fatalf("%s holds %q, not a dump that was sent", path, data)
+			}
+			if string(data) == want {
+				return
+			}
+		} else if !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s never held %q", path, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDebugDumperConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	d := newDebugDumper(dir)
+
+	clients := []string{"alice", "bob"}
+	sent := map[string]bool{}
+	for _, client := range clients {
+		// half the queue, leaving room for the last dumps
+		for i := range debugDumpQueueSize / 2 / len(clients) {
+			sent[fmt.Sprintf(`{"client":%q,"n":%d,"pad":%q}`, client, i, strings.Repeat("x", 64<<10))] = true
+		}
+	}
+
+	var wg sync.WaitGroup
+	for data := range sent {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var dump struct{ Client string }
+			json.Unmarshal([]byte(data), &dump)
+			d.dump(zerolog.Nop(), dump.Client, []byte(data))
+		}()
+	}
+	wg.Wait()
+
+	// dumps are written in order, once the last one is in, every other one was written
+	for _, client := range clients {
+		last := fmt.Sprintf(`{"client":%q,"last":true}`, client)
+		sent[last] = true
+		d.dump(zerolog.Nop(), client, []byte(last))
+		waitForDump(t, dir, client, last, sent)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(clients) {
+		t.Errorf("the dump directory holds %d files, want one per client", len(entries))
+	}
+}
+
+func TestDebugDumperDisabled(t *testing.T) {
+	d := newDebugDumper("")
+	if d != nil {
+		t.Fatal("dumps are enabled without a directory")
+	}
+	d.dump(zerolog.Nop(), "client", []byte("{}"))
+}
+
+func TestDebugDumpPath(t *testing.T) {
+	dir := t.TempDir()
+	for _, id := range []string{"../../etc/passwd", "a b/c", ""} {
+		path := debugDumpPath(dir, id)
+		if filepath.Dir(path) != dir || !strings.HasSuffix(path, "."+debugCodeContextFile) {
+			t.Errorf("%q dumps to %s, outside of %s", id, path, dir)
+		}
+	}
+	if debugDumpPath(dir, "a") == debugDumpPath(dir, "b") {
+		t.Error("two clients share a dump file")
+	}
+}
+
+func TestHandlerDumpsConcurrentPreloads(t *testing.T) {
+	dir := t.TempDir()
+	svc := NewCodeContextService(streamingModel{content: `{"step":"load","status":"ok"}`}, "test-model", dir, nil, 0, 0, 0, 1, 1<<20)
+	srv := httptest.NewServer(http.HandlerFunc(svc.Handler(context.Background())))
+	t.Cleanup(srv.Close)
+	addr := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	// preloadContext is the context of the client's nth preload
+	preloadContext := func(client string, n int) ctxtypes.ApplicationContext {
+		return ctxtypes.ApplicationContext{Root: fmt.Sprintf("/%s/%d", client, n), FileContents: map[string]string{"a.go": strings.Repeat("x", 32<<10)}}
+	}
+
+	clients := []string{"alice", "bob"}
+	sent := map[string]bool{}
+	for _, client := range clients {
+		for n := range 4 {
+			data, _ := json.Marshal(preloadContext(client, n))
+			sent[string(data)] = true
+		}
+	}
+
+	// each client preloads from several connections at once
+	var wg sync.WaitGroup
+	for _, client := range clients {
+		for n := range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c, _, err := websocket.DefaultDialer.Dial(addr, nil)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer c.Close()
+				req := ctxtypes.CtxRequest{ClientID: client, Step: ctxtypes.CtxStepLoadContext, Context: preloadContext(client, n)}
+				// preloads aren't answered
+				if err := c.WriteJSON(req); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	for _, client := range clients {
+		data, _ := json.Marshal(preloadContext(client, 99))
+		sent[string(data)] = true
+		c, _, err := websocket.DefaultDialer.Dial(addr, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.WriteJSON(ctxtypes.CtxRequest{ClientID: client, Step: ctxtypes.CtxStepLoadContext, Context: preloadContext(client, 99)}); err != nil {
+			t.Fatal(err)
+		}
+		waitForDump(t, dir, client, string(data), sent)
+		c.Close()
+	}
+}
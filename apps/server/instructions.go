@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+// buildInstructions returns the instructions sent to the model along with the
+// application context, followed by the user's extra instructions. Unknown steps
+// yield no instructions. maxAdditional caps the additional context files of a
+// selection, 0 disables.
+func buildInstructions(req ctxtypes.CtxRequest, maxAdditional int) []string {
+	var instructions []string
+	switch req.Step {
+	case ctxtypes.CtxStepLoadContext:
+		return preloadInstructions(req)
+	case ctxtypes.CtxStepPlan:
+		instructions = planInstructions(req)
+	case ctxtypes.CtxStepFileSelection:
+		instructions = selectInstructions(req, maxAdditional)
+	case ctxtypes.CtxStepCodeWork:
+		instructions = workInstructions(req)
+	case ctxtypes.CtxStepReview:
+		instructions = reviewInstructions(req)
+	default:
+		return nil
+	}
+	return append(instructions, extraInstructions(req)...)
+}
+
+// responseSchema returns the JSON schema the model is asked to respond with, nil for unknown steps
+func responseSchema(req ctxtypes.CtxRequest) interface{} {
+	switch req.Step {
+	case ctxtypes.CtxStepLoadContext:
+		return GenerateSchema[ctxtypes.StepPreloadResponseSchema]()
+	case ctxtypes.CtxStepPlan:
+		return GenerateSchema[ctxtypes.StepPlanData]()
+	case ctxtypes.CtxStepFileSelection:
+		if req.Explain {
+			return GenerateSchema[ctxtypes.StepFileSelectExplainedFiles]()
+		}
+		return GenerateSchema[ctxtypes.StepFileSelectFiles]()
+	case ctxtypes.CtxStepCodeWork:
+		switch {
+		case req.WorkFormat == ctxtypes.WorkFormatEdits:
+			return GenerateSchema[ctxtypes.EditData]()
+		case req.WithTests:
+			return GenerateSchema[ctxtypes.PatchDataWithTests]()
+		}
+		return GenerateSchema[ctxtypes.PatchData]()
+	case ctxtypes.CtxStepReview:
+		return GenerateSchema[ctxtypes.StepReviewData]()
+	}
+	return nil
+}
+
+// extraInstructions returns the one-off constraints provided by the user with -instruction
+func extraInstructions(req ctxtypes.CtxRequest) []string {
+	instructions := []string{}
+	for _, extra := range req.ExtraInstructions {
+		if extra = strings.TrimSpace(extra); extra != "" {
+			instructions = append(instructions, fmt.Sprintf("The user adds this constraint, which takes precedence over general guidance: ``%s``.", extra))
+		}
+	}
+	return instructions
+}
+
+// preloadInstructions asks the model to acknowledge the context
+func preloadInstructions(req ctxtypes.CtxRequest) []string {
+	schema := responseSchema(req)
+
+	return []string{
+		"Acknowledge application context and respond step=preload and status=ok",
+		fmt.Sprintf("Respond using this JSON schema: %v", schema),
+	}
+}
+
+// planInstructions asks the model for an implementation plan, reviewed by the user before any change is made
+func planInstructions(req ctxtypes.CtxRequest) []string {
+	schema := responseSchema(req)
+
+	return []string{
+		fmt.Sprintf("You are a senior software engineer and system architect. Consider the previously provided application context along with this user prompt describing changes needed to the codebase: ``%s``.", req.UserPrompt),
+		"Do not write any code yet. Return an implementation plan for the user to review: a short `summary` of the approach, then the ordered `steps` needed to implement it. Each step describes one change and lists the paths of the files it affects in `files`, including files to create, remove or move.",
+		"Call out assumptions and ambiguities of the prompt in the summary so that misunderstandings are caught before the work starts.",
+		fmt.Sprintf("Respond using this JSON schema: %v", schema),
+	}
+}
+
+// planReminder returns the instructions enforcing the plan approved by the user, if any
+func planReminder(req ctxtypes.CtxRequest) []string {
+	if req.Plan == nil {
+		return nil
+	}
+
+	plan, err := json.Marshal(req.Plan)
+	if err != nil {
+		return nil
+	}
+	return []string{
+		fmt.Sprintf("The user approved this implementation plan. Follow it and stay within the files it lists: %s", plan),
+	}
+}
+
+// selectInstructions asks the model for the files to change and the files to use as context
+func selectInstructions(req ctxtypes.CtxRequest, maxAdditional int) []string {
+	schema := responseSchema(req)
+
+	instructions := []string{
+		fmt.Sprintf("You are a senior software engineer and system architect. Consider the previously provided application context along with this user prompt describing changes needed to the codebase: ``%s``.", req.UserPrompt),
+		"First identity the list of files that will need to be altered, created or removed in order to implement the requirements or instructions articulated in the prompt. Return these in the `files` array. The `operation` field must be set to 0 for updates, 1 for create, -1 for remove and 2 for move.",
+		"A file that is renamed or relocated must be returned as a single move operation with `NewPath` set to its destination, never as a remove and a create. Content changes to a moved file are made at its new path.",
+		"Next identity additional files for which the content would be useful to have in order to perform the requested changes. Return this list of files in the `additional_context_files` array.",
+		"Files listed in `pinned` were explicitly provided by the user and their content is already in `file_contents`. Always use them as additional context, there is no need to return them in `additional_context_files`.",
+		"Files listed in `references` are reference documents, not code. Use them for grounding only and never return them in `files` or `additional_context_files`.",
+		"Files may carry a `role`: source, test, config, docs, build or lockfile. A lockfile's keywords are the dependency versions it pins, only select it when the prompt is about dependencies. When the behavior of source files changes, return their test files in `additional_context_files`, or in `files` if they need updating. Prefer source files over docs, config and build files unless the prompt is about those.",
+		"If no file needs to change, or the files to change can't be identified, return an empty `files` array and explain why in `reason`.",
+		fmt.Sprintf("Respond using this JSON schema: %v", schema),
+	}
+
+	if len(req.Hints) > 0 {
+		instructions = append(instructions, fmt.Sprintf("The user prompt explicitly mentions these files, which are likely to be part of the change: %s", strings.Join(req.Hints, ", ")))
+	}
+
+	if maxAdditional > 0 {
+		instructions = append(instructions, fmt.Sprintf("Return at most %d files in `additional_context_files`, the most useful first. Prioritize files defining the types and functions the changes rely on over loosely related ones.", maxAdditional))
+	}
+
+	if req.Explain {
+		instructions = append(instructions, "For each file returned, list in `matched_keywords` the keywords, path segments or prompt terms that led to its selection, and rate in `relevance` how relevant it is to the prompt, from 0 to 1.")
+	}
+
+	if req.NoKeywords {
+		instructions = append(instructions, "The context has no keywords, only the paths of files and directories. Infer what each file holds from its path, name and extension, and favor files whose names match the concepts in the prompt.")
+	}
+
+	if req.NoContents {
+		instructions = append(instructions, "The user does not share file contents, only paths and keywords. Base the selection on those and return an empty `additional_context_files` array since their content would not be provided.")
+	}
+
+	return append(instructions, planReminder(req)...)
+}
+
+// workInstructions asks the model for the patch of the work target
+func workInstructions(req ctxtypes.CtxRequest) []string {
+	if req.WorkFormat == ctxtypes.WorkFormatEdits {
+		instructions := append(editInstructions(req), personaInstructions(req)...)
+		return append(append(instructions, noContentsInstructions(req)...), planReminder(req)...)
+	}
+
+	schema := responseSchema(req)
+
+	instructions := []string{
+		fmt.Sprintf("You are a senior software engineer and system architect. Consider the previously provided application context along with this user prompt describing changes needed to the codebase: ``%s``.", req.UserPrompt),
+		"You always follow best practices and ensure that your code is clean, maintainable, and well-documented. Your code should be production-ready and ready to be reviewed by your peers. Changes are razor-focused and should not include any unrelated changes.",
+		fmt.Sprintf("Respond using a properly formatted git patch, honoring the following schema: %v", schema),
+		describeWorkTarget(req.LineFormat, "patch"),
+		fmt.Sprintf("Given the application context and the user prompt, return the changes needed to implement the requirements or instructions articulated in the prompt for the file: \n\n%s", formatWorkTarget(req.WorkTarget, req.LineFormat)),
+	}
+
+	instructions = append(instructions,
+		"Rate your `confidence`, from 0 to 1, that the patch is correct and complete, and list in `assumptions` what you assumed to write it, e.g. the behavior of code not in the context or an ambiguous requirement. Leave `assumptions` empty when there are none.")
+	instructions = append(instructions, personaInstructions(req)...)
+	instructions = append(instructions, noContentsInstructions(req)...)
+	instructions = append(instructions, planReminder(req)...)
+
+	if req.WithTests {
+		instructions = append(instructions, "When the changes add or modify behavior, also return a git patch for the corresponding test file in the `tests` array, setting its `path`. Follow the language's conventions for test file names and locations, e.g. `foo_test.go` next to `foo.go`, `foo.test.ts` next to `foo.ts`, `test_foo.py` for `foo.py`. Extend an existing test file found in the application context rather than creating a new one. Return an empty `tests` array if no test changes are warranted.")
+	}
+
+	return instructions
+}
+
+// editInstructions asks the model for the changes of the work target as text edits
+func editInstructions(req ctxtypes.CtxRequest) []string {
+	schema := responseSchema(req)
+
+	return []string{
+		fmt.Sprintf("You are a senior software engineer and system architect. Consider the previously provided application context along with this user prompt describing changes needed to the codebase: ``%s``.", req.UserPrompt),
+		"You always follow best practices and ensure that your code is clean, maintainable, and well-documented. Your code should be production-ready and ready to be reviewed by your peers. Changes are razor-focused and should not include any unrelated changes.",
+		fmt.Sprintf("Respond with the list of text edits to apply to the file, honoring the following schema: %v", schema),
+		fmt.Sprintf("Each edit replaces the text between `range.start` and `range.end` with `newText`. Lines and characters are zero-based and the end is exclusive: %s is line 0. Use an empty range to insert text and an empty `newText` to delete text. Edits must not overlap.", firstLine(req.LineFormat)),
+		describeWorkTarget(req.LineFormat, "edits") + " For a file that does not exist yet, return a single edit inserting the whole content at line 0, character 0.",
+		fmt.Sprintf("Given the application context and the user prompt, return the changes needed to implement the requirements or instructions articulated in the prompt for the file: \n\n%s", formatWorkTarget(req.WorkTarget, req.LineFormat)),
+	}
+}
+
+// reviewInstructions asks the model to comment on the changes in the diff rather than edit them
+func reviewInstructions(req ctxtypes.CtxRequest) []string {
+	schema := responseSchema(req)
+
+	return []string{
+		fmt.Sprintf("You are a senior software engineer reviewing a change before it is committed. Consider the previously provided application context, which holds the changed files and related files, along with these review instructions: ``%s``.", req.UserPrompt),
+		fmt.Sprintf("Review the following diff. Do not rewrite the code, return review comments only:\n\n%s", req.Diff),
+		"Each comment targets a line of the new version of a changed file: set `path` to the file path and `line` to its 1-based line number. Focus on bugs, security issues and maintainability, not style. Set `severity` to `error` for bugs and security issues, `warning` for likely problems and `info` for suggestions. Return an empty `comments` array when there is nothing to report.",
+		fmt.Sprintf("Respond using this JSON schema: %v", schema),
+	}
+}
+
+// noContentsInstructions warns the model that only the work target's content is available
+func noContentsInstructions(req ctxtypes.CtxRequest) []string {
+	if !req.NoContents {
+		return nil
+	}
+	return []string{
+		"The user does not share file contents. Only the target file's content is provided, other files are known by their path and keywords alone. Do not assume anything about their content beyond what the keywords indicate, and keep the changes to the target file.",
+	}
+}
+
+// describeWorkTarget explains how the work target is presented, its lines numbered according to format.
+// output names what the model returns, e.g. "patch".
+func describeWorkTarget(format ctxtypes.LineFormat, output string) string {
+	if format == ctxtypes.LineFormatNone {
+		return fmt.Sprintf("The target file is described below. When its current content is provided, it follows the `Current content:` header as is. The description headers must not appear in the %s.", output)
+	}
+	return fmt.Sprintf("The target file is described below. When its current content is provided, each line is prefixed with its line number, e.g. `%s` for the first line. The prefix is not part of the file and must not appear in the %s, nor should the description headers.", format.Prefix(1), output)
+}
+
+// firstLine names the first line of the work target content for the model
+func firstLine(format ctxtypes.LineFormat) string {
+	if format == ctxtypes.LineFormatNone {
+		return "the first line of the current content"
+	}
+	return fmt.Sprintf("the line prefixed with `%s`", format.Prefix(1))
+}
+
+// formatWorkTarget renders the work target for the model, numbering its lines
+// according to format. The output only depends on its arguments.
+func formatWorkTarget(t *ctxtypes.WorkTarget, format ctxtypes.LineFormat) string {
+	if t == nil {
+		return "(no target file provided)"
+	}
+
+	b := strings.Builder{}
+	b.WriteString(fmt.Sprintf("Path: %s\nOperation: %s\n", t.Path, t.Operation))
+
+	if t.Operation == ctxtypes.FileOperationCreate {
+		b.WriteString("The file does not exist yet.\n")
+		return b.String()
+	}
+
+	b.WriteString("Current content:\n")
+	scanner := bufio.NewScanner(strings.NewReader(t.Content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(t.Content)+1)
+	for n := 1; scanner.Scan(); n++ {
+		b.WriteString(format.Prefix(n) + scanner.Text() + "\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+func TestBuildInstructions(t *testing.T) {
+	target := &ctxtypes.WorkTarget{Path: "a.go", Content: "package a\n"}
+
+	tests := []struct {
+		name          string
+		req           ctxtypes.CtxRequest
+		maxAdditional int
+		// want are found in the instructions, in order, wantNot in none of them
+		want    []string
+		wantNot []string
+	}{
+		{
+			name: "preload",
+			req:  ctxtypes.CtxRequest{Step: ctxtypes.CtxStepLoadContext, ExtraInstructions: []string{"ignored"}},
+			want: []string{"Acknowledge application context and respond step=preload and status=ok", "Respond using this JSON schema"},
+			// the extra instructions are for the steps answering the prompt
+			wantNot: []string{"ignored"},
+		},
+		{
+			name: "plan",
+			req:  ctxtypes.CtxRequest{Step: ctxtypes.CtxStepPlan, UserPrompt: "add a cache"},
+			want: []string{"user prompt describing changes needed to the codebase: ``add a cache``", "Do not write any code yet", "Respond using this JSON schema"},
+		},
+		{
+			name:    "select",
+			req:     ctxtypes.CtxRequest{Step: ctxtypes.CtxStepFileSelection, UserPrompt: "add a cache"},
+			want:    []string{"``add a cache``", "Return these in the `files` array", "Respond using this JSON schema"},
+			wantNot: []string{"at most", "matched_keywords", "explicitly mentions", "approved this implementation plan"},
+		},
+		{
+			name: "select with options",
+			req: ctxtypes.CtxRequest{Step: ctxtypes.CtxStepFileSelection, UserPrompt: "add a cache", Hints: []string{"cache.go", "main.go"},
+				Explain: true, NoKeywords: true, NoContents: true, Plan: &ctxtypes.StepPlanData{Summary: "an LRU"}},
+			maxAdditional: 3,
+			want: []string{"``add a cache``", "explicitly mentions these files, which are likely to be part of the change: cache.go, main.go",
+				"Return at most 3 files in `additional_context_files`", "matched_keywords", "The context has no keywords", "The user does not share file contents",
+				`approved this implementation plan. Follow it and stay within the files it lists: {"summary":"an LRU"`},
+		},
+		{
+			name: "work",
+			req:  ctxtypes.CtxRequest{Step: ctxtypes.CtxStepCodeWork, UserPrompt: "add a cache", WorkTarget: target},
+			want: []string{"``add a cache``", "Respond using a properly formatted git patch", "prefixed with its line number, e.g. `1 | `",
+				"for the file: \n\nPath: a.go\nOperation: update\nCurrent content:\n1 | package a\n", "Rate your `confidence`"},
+			wantNot: []string{"`tests` array", "codebase is a"},
+		},
+		{
+			name: "work with tests and personas",
+			req: ctxtypes.CtxRequest{Step: ctxtypes.CtxStepCodeWork, UserPrompt: "add a cache", WorkTarget: target, LineFormat: ctxtypes.LineFormatNone,
+				WithTests: true, ProjectTypes: []string{"go", "node"}, Personas: map[string]string{"node": " "}, ExtraInstructions: []string{" no new deps "}},
+			want: []string{"follows the `Current content:` header as is", "Current content:\npackage a\n", "The codebase is a go project. Write idiomatic Go",
+				"`tests` array", "The user adds this constraint, which takes precedence over general guidance: ``no new deps``."},
+			// an empty persona disables the built-in one
+			wantNot: []string{"codebase is a node project"},
+		},
+		{
+			name: "edits",
+			req:  ctxtypes.CtxRequest{Step: ctxtypes.CtxStepCodeWork, UserPrompt: "add a cache", WorkTarget: &ctxtypes.WorkTarget{Path: "b.go", Operation: ctxtypes.FileOperationCreate}, WorkFormat: ctxtypes.WorkFormatEdits, LineFormat: ctxtypes.LineFormatBracket},
+			want: []string{"Respond with the list of text edits", "the line prefixed with `⟦1⟧ ` is line 0", "inserting the whole content at line 0",
+				"Path: b.go\nOperation: create\nThe file does not exist yet.\n"},
+			wantNot: []string{"git patch", "Current content:"},
+		},
+		{
+			name:    "review",
+			req:     ctxtypes.CtxRequest{Step: ctxtypes.CtxStepReview, UserPrompt: "check errors", Diff: "+panic(err)"},
+			want:    []string{"review instructions: ``check errors``", "Do not rewrite the code, return review comments only:\n\n+panic(err)", "Respond using this JSON schema"},
+			wantNot: []string{"git patch"},
+		},
+	}
+	for _, tt := range tests {
+		instructions := buildInstructions(tt.req, tt.maxAdditional)
+		text := strings.Join(instructions, "\n")
+
+		rest := text
+		for _, want := range tt.want {
+			i := strings.Index(rest, want)
+			if i < 0 {
+				t.Errorf("%s: %q is missing or out of order in:\n%s", tt.name, want, text)
+				break
+			}
+			rest = rest[i+len(want):]
+		}
+		for _, wantNot := range tt.wantNot {
+			if strings.Contains(text, wantNot) {
+				t.Errorf("%s: %q found in:\n%s", tt.name, wantNot, text)
+			}
+		}
+	}
+}
+
+func TestBuildInstructionsExtraLast(t *testing.T) {
+	for _, step := range []ctxtypes.CtxStep{ctxtypes.CtxStepPlan, ctxtypes.CtxStepFileSelection, ctxtypes.CtxStepCodeWork, ctxtypes.CtxStepReview} {
+		instructions := buildInstructions(ctxtypes.CtxRequest{Step: step, ExtraInstructions: []string{"first", "", "second"}}, 0)
+		n := len(instructions)
+		if n < 3 || !strings.Contains(instructions[n-2], "``first``") || !strings.Contains(instructions[n-1], "``second``") {
+			t.Errorf("%s: the extra instructions don't come last in %q", step, instructions)
+		}
+	}
+}
+
+func TestBuildInstructionsUnknownStep(t *testing.T) {
+	if got := buildInstructions(ctxtypes.CtxRequest{Step: ctxtypes.CtxStepStatus}, 0); got != nil {
+		t.Errorf("got %q for the status step", got)
+	}
+}
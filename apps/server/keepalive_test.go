@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	ctxutils "github.com/cyber-nic/ctx/libs/utils"
+	"github.com/gorilla/websocket"
+)
+
+const testPingInterval = 20 * time.Millisecond
+
+// dialKeepalive connects to a service pinging every testPingInterval, asking for keepalive
+func dialKeepalive(t *testing.T) *websocket.Conn {
+	t.Helper()
+	svc := NewCodeContextService(streamingModel{}, "test-model", "", nil, 0, 0, testPingInterval, 1, 1<<20)
+	srv := httptest.NewServer(http.HandlerFunc(svc.Handler(context.Background())))
+	t.Cleanup(srv.Close)
+
+	header := http.Header{ctxtypes.FeatureHeader: {ctxtypes.FeatureKeepalive}}
+	c, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	if got := resp.Header.Get(ctxtypes.PingIntervalHeader); got != testPingInterval.String() {
+		t.Fatalf("got ping interval %q, want %s", got, testPingInterval)
+	}
+	return c
+}
+
+// sendManifest sends a manifest request and reads up to its response
+func sendManifest(c *websocket.Conn) error {
+	req := ctxtypes.CtxRequest{ClientID: "client", Step: ctxtypes.CtxStepManifest, Manifest: map[string]string{"a.go": "h"}}
+	if err := c.WriteJSON(req); err != nil {
+		return err
+	}
+	c.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var resp ctxtypes.StepStatusResponseSchema
+		if err := c.ReadJSON(&resp); err != nil {
+			return err
+		}
+		if resp.Step == string(ctxtypes.CtxStepManifest) {
+			return nil
+		}
+	}
+}
+
+func TestHandlerKeepaliveIdle(t *testing.T) {
+	c := dialKeepalive(t)
+	ctxutils.KeepAlive(c, testPingInterval)
+
+	// idle, not even reading, well past the wait after which a silent client is dropped
+	time.Sleep(10 * ctxutils.KeepAliveWait(testPingInterval))
+
+	if err := sendManifest(c); err != nil {
+		t.Fatalf("the idle connection was dropped: %v", err)
+	}
+}
+
+func TestHandlerKeepaliveDropsSilentClient(t *testing.T) {
+	c := dialKeepalive(t)
+
+	// neither pinging nor reading, so the server's pings go unanswered
+	time.Sleep(10 * ctxutils.KeepAliveWait(testPingInterval))
+
+	err := sendManifest(c)
+	var netErr net.Error
+	if err == nil || (errors.As(err, &netErr) && netErr.Timeout()) {
+		t.Fatalf("got %v, want the connection closed by the server", err)
+	}
+}
@@ -3,60 +3,98 @@ package main
 import (
 	"context"
 	"flag"
-	"fmt"
 	"net/http"
 	"os"
+	"time"
 
+	ctxtelemetry "github.com/cyber-nic/ctx/libs/telemetry"
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
 	ctxutils "github.com/cyber-nic/ctx/libs/utils"
 
 	"github.com/rs/zerolog/log"
-	"github.com/tmc/langchaingo/llms/googleai"
 )
 
 const (
-	// this is the model name that we are using and should NEVER be changed
-	modelName            = "gemini-2.0-flash-exp"
+	// defaultModel is the model the prompts are tuned for, see resolveModel
+	defaultModel         = "gemini-2.0-flash-exp"
 	debugCodeContextFile = "code.ctx"
 )
 
 func main() {
 	var addr = flag.String("addr", "localhost:8000", "http service address")
 	var debug = flag.Bool("debug", false, "enable debug mode")
+	var debugDump = flag.String("debug-dump", "", "directory to dump each client's received context to (disabled if empty)")
+	var selectMaxTokens = flag.Int("select-max-tokens", 4096, "max output tokens of the plan and select steps (0 uses the provider default)")
+	var workMaxTokens = flag.Int("work-max-tokens", 8192, "max output tokens of the work and review steps (0 uses the provider default)")
+	var maxAdditional = flag.Int("max-additional-files", 10, "max additional context files the select step returns (0 disables)")
+	var providerName = flag.String("provider", "googleai", "LLM provider: googleai, openai or anthropic")
+	var keyFile = flag.String("key-file", "", "file to read the API key from when neither CTX_API_KEY nor the provider's env var is set (default ~/.secrets/<provider key>)")
+	var model = flag.String("model", "", "model to use, e.g. gemini-1.5-flash (env CTX_MODEL, default "+defaultModel+" or the provider's default)")
+	var harmThreshold = flag.String("harm-threshold", "high", "gemini safety filter threshold applied to all harm categories: none, high, medium or low")
+	var tokenBudget = flag.Int("client-token-budget", 0, "total tokens each client can use, further requests are rejected (0 disables)")
+	var generateAttempts = flag.Int("generate-attempts", 3, "tries of a generation failing with a rate limit or another transient provider error, backing off between them (1 disables retries)")
+	var cacheSize = flag.Int("content-cache-size", 256<<20, "max bytes of uploaded file contents cached for all clients, the least recently used are evicted first")
+	var pingInterval = flag.Duration("ping-interval", 30*time.Second, "ping clients asking for keepalive this often, dropping those silent for two intervals (0 disables)")
+	var otlpEndpoint = flag.String("otlp-endpoint", "", "export OpenTelemetry traces to this OTLP/HTTP endpoint, e.g. http://localhost:4318 (disabled if empty)")
 	flag.Parse()
 
 	ctxutils.ConfigLogging(debug)
 
+	threshold, err := parseHarmThreshold(*harmThreshold)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid flag")
+	}
+
+	provider, err := parseProvider(*providerName)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid flag")
+	}
+
 	// context
 	ctx := context.Background()
 
-	// API key
-	var key []byte
-	{
-		// get home dir
-		homedir, err := os.UserHomeDir()
-		if err != nil {
-			log.Fatal().Err(err).Msg("failed to locate user's home directory")
-		}
+	shutdownTracing, err := ctxtelemetry.Setup(ctx, "ctx-server", *otlpEndpoint)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to set up tracing")
+	}
+	defer shutdownTracing(ctx)
 
-		// read API key
-		if key, err = os.ReadFile(fmt.Sprintf("%s/.secrets/GCP_AI_API_KEY", homedir)); err != nil {
-			log.Fatal().Err(err).Msg("failed to read API key")
-		}
+	// API key
+	key, err := provider.readKey(*keyFile)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to read API key")
 	}
 
-	llm, err := googleai.New(ctx, googleai.WithAPIKey(string(key)))
+	llm, err := provider.new(ctx, key, threshold)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to create AI client")
 	}
 
 	// create a new CodeContextService
-	wss := NewCodeContextService(llm, modelName)
+	modelID := resolveModel(*model, provider)
+	wss := NewCodeContextService(llm, modelID, *debugDump, map[ctxtypes.CtxStep]int{
+		ctxtypes.CtxStepPlan:          *selectMaxTokens,
+		ctxtypes.CtxStepFileSelection: *selectMaxTokens,
+		ctxtypes.CtxStepCodeWork:      *workMaxTokens,
+		ctxtypes.CtxStepReview:        *workMaxTokens,
+	}, *maxAdditional, *tokenBudget, *pingInterval, *generateAttempts, *cacheSize)
 
 	// Start server
 	http.HandleFunc("/data", wss.Handler(ctx))
 
-	log.Info().Str("proto", "ws").Str("addr", *addr).Msg("listening")
+	log.Info().Str("proto", "ws").Str("addr", *addr).Str("provider", *providerName).Str("model", modelID).Msg("listening")
 	if err := http.ListenAndServe(*addr, nil); err != nil {
 		log.Fatal().Err(err).Msg("failed to start server")
 	}
 }
+
+// resolveModel picks the model by precedence: flag, CTX_MODEL, the provider's default
+func resolveModel(flagModel string, p provider) string {
+	if flagModel != "" {
+		return flagModel
+	}
+	if model := os.Getenv("CTX_MODEL"); model != "" {
+		return model
+	}
+	return p.defaultModel
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	"github.com/gorilla/websocket"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestResolveModel(t *testing.T) {
+	tests := []struct {
+		name      string
+		flag, env string
+		provider  string
+		want      string
+	}{
+		{name: "flag first", flag: "gemini-1.5-flash", env: "gemini-1.5-pro", provider: "googleai", want: "gemini-1.5-flash"},
+		{name: "env next", env: "gemini-1.5-pro", provider: "googleai", want: "gemini-1.5-pro"},
+		{name: "googleai default", provider: "googleai", want: defaultModel},
+		{name: "other provider default", provider: "openai", want: providers["openai"].defaultModel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CTX_MODEL", tt.env)
+			if got := resolveModel(tt.flag, providers[tt.provider]); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// recordingModel records the model of each generation
+type recordingModel struct {
+	streamingModel
+
+	mu     sync.Mutex
+	models []string
+}
+
+func (m *recordingModel) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	opts := llms.CallOptions{}
+	for _, o := range options {
+		o(&opts)
+	}
+	m.mu.Lock()
+	m.models = append(m.models, opts.Model)
+	m.mu.Unlock()
+	return m.streamingModel.GenerateContent(ctx, messages, options...)
+}
+
+func (m *recordingModel) Call(ctx context.Context, prompt string, opts ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, opts...)
+}
+
+func TestHandlerUsesConfiguredModel(t *testing.T) {
+	llm := &recordingModel{streamingModel: streamingModel{content: `{"summary":"one step","steps":[]}`}}
+	svc := NewCodeContextService(llm, "gemini-1.5-flash", "", nil, 0, 0, 0, 1, 1<<20)
+	srv := httptest.NewServer(http.HandlerFunc(svc.Handler(context.Background())))
+	t.Cleanup(srv.Close)
+
+	c, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	if err := c.WriteJSON(ctxtypes.CtxRequest{ClientID: "client", Step: ctxtypes.CtxStepPlan, UserPrompt: "plan it"}); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		var resp ctxtypes.StepStatusResponseSchema
+		if err := c.ReadJSON(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Step == string(ctxtypes.CtxStepPlan) {
+			break
+		}
+	}
+
+	llm.mu.Lock()
+	defer llm.mu.Unlock()
+	if len(llm.models) == 0 {
+		t.Fatal("nothing was generated")
+	}
+	for _, model := range llm.models {
+		if model != "gemini-1.5-flash" {
+			t.Errorf("generated with model %q, want the configured gemini-1.5-flash", model)
+		}
+	}
+}
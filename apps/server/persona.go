@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+// defaultPersonas is the work step guidance for each project type, see ctxexcludes.Profiles
+var defaultPersonas = map[string]string{
+	"go":     "Write idiomatic Go: wrap errors with context using fmt.Errorf and %w, return errors rather than panicking, keep interfaces small and accept them where they are used, and let gofmt decide the formatting.",
+	"node":   "Write modern JavaScript or TypeScript: prefer const, async/await over callbacks and promise chains, ES modules, and for React, functional components with hooks. Keep types strict when the project uses TypeScript.",
+	"python": "Write idiomatic Python: follow PEP 8, add type hints to new functions, prefer context managers for resources, and raise specific exceptions rather than returning error values.",
+	"django": "Follow Django conventions: keep business logic in models or services rather than views, use the ORM over raw SQL, and never edit generated migrations by hand.",
+	"java":   "Write idiomatic Java: prefer immutability and constructor injection, use Optional rather than returning null, and follow the project's existing package layout.",
+	"rust":   "Write idiomatic Rust: propagate errors with ? and Result rather than unwrap, prefer borrowing over cloning, and keep unsafe code out unless the project already relies on it.",
+}
+
+// personaInstructions returns the guidance of the request's project types, a
+// client persona replacing the built-in one of its type
+func personaInstructions(req ctxtypes.CtxRequest) []string {
+	instructions := []string{}
+	for _, projectType := range req.ProjectTypes {
+		persona, ok := req.Personas[projectType]
+		if !ok {
+			persona = defaultPersonas[projectType]
+		}
+		if persona = strings.TrimSpace(persona); persona != "" {
+			instructions = append(instructions, fmt.Sprintf("The codebase is a %s project. %s", projectType, persona))
+		}
+	}
+	return instructions
+}
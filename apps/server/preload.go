@@ -0,0 +1,43 @@
+package main
+
+import (
+	"sync"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+// preloadRecord is the last preload processed for a client
+type preloadRecord struct {
+	hash string
+	ack  ctxtypes.StepPreloadResponseSchema
+}
+
+// preloadCache remembers each client's last preloaded context so that
+// periodic re-preloads of an unchanged context are not processed again.
+type preloadCache struct {
+	mu      sync.Mutex
+	records map[string]preloadRecord
+}
+
+func newPreloadCache() *preloadCache {
+	return &preloadCache{records: map[string]preloadRecord{}}
+}
+
+// lookup returns the cached ack if the client's last preload had the same hash
+func (c *preloadCache) lookup(clientID, hash string) (ctxtypes.StepPreloadResponseSchema, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r, ok := c.records[clientID]
+	if !ok || r.hash != hash {
+		return ctxtypes.StepPreloadResponseSchema{}, false
+	}
+	return r.ack, true
+}
+
+func (c *preloadCache) store(clientID, hash string, ack ctxtypes.StepPreloadResponseSchema) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.records[clientID] = preloadRecord{hash: hash, ack: ack}
+}
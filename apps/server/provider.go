@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/anthropic"
+	"github.com/tmc/langchaingo/llms/googleai"
+	"github.com/tmc/langchaingo/llms/openai"
+)
+
+// provider creates the model client of an LLM provider
+type provider struct {
+	// defaultModel is used when neither -model nor CTX_MODEL is set
+	defaultModel string
+	// keyFile is the name of the API key file in ~/.secrets
+	keyFile string
+	new     func(ctx context.Context, key string, threshold googleai.HarmBlockThreshold) (llms.Model, error)
+}
+
+// providers maps the -provider flag values to their provider. Only googleai
+// applies the -harm-threshold safety filters.
+var providers = map[string]provider{
+	"googleai": {
+		defaultModel: defaultModel,
+		keyFile:      "GCP_AI_API_KEY",
+		new: func(ctx context.Context, key string, threshold googleai.HarmBlockThreshold) (llms.Model, error) {
+			return googleai.New(ctx, googleai.WithAPIKey(key), googleai.WithHarmThreshold(threshold))
+		},
+	},
+	"openai": {
+		defaultModel: "gpt-4o",
+		keyFile:      "OPENAI_API_KEY",
+		new: func(_ context.Context, key string, _ googleai.HarmBlockThreshold) (llms.Model, error) {
+			return openai.New(openai.WithToken(key))
+		},
+	},
+	"anthropic": {
+		defaultModel: "claude-3-5-sonnet-20240620",
+		keyFile:      "ANTHROPIC_API_KEY",
+		new: func(_ context.Context, key string, _ googleai.HarmBlockThreshold) (llms.Model, error) {
+			return anthropic.New(anthropic.WithToken(key))
+		},
+	},
+}
+
+// parseProvider returns the provider for a flag value
+func parseProvider(s string) (provider, error) {
+	if p, ok := providers[strings.ToLower(s)]; ok {
+		return p, nil
+	}
+
+	names := make([]string, 0, len(providers))
+	for n := range providers {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	return provider{}, fmt.Errorf("invalid provider %q, expected one of %s", s, strings.Join(names, ", "))
+}
+
+// apiKeyEnv holds the API key of any provider, taking precedence over the provider's own env var
+const apiKeyEnv = "CTX_API_KEY"
+
+// keyPath returns the key file flag value, or ~/.secrets/<keyFile> if empty
+func (p provider) keyPath(flagPath string) (string, error) {
+	if flagPath != "" {
+		return flagPath, nil
+	}
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user's home directory: %w", err)
+	}
+	return filepath.Join(homedir, ".secrets", p.keyFile), nil
+}
+
+// readKey returns the provider's API key by precedence: CTX_API_KEY, the
+// env var named after the key file, e.g. GCP_AI_API_KEY, then the key file
+func (p provider) readKey(keyFile string) (string, error) {
+	for _, env := range []string{apiKeyEnv, p.keyFile} {
+		if key := strings.TrimSpace(os.Getenv(env)); key != "" {
+			return key, nil
+		}
+	}
+
+	path, err := p.keyPath(keyFile)
+	if err != nil {
+		return "", err
+	}
+	key, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("no API key: set %s or %s, or write it to %s", apiKeyEnv, p.keyFile, path)
+	}
+	if err != nil {
+		return "", err
+	}
+	if k := strings.TrimSpace(string(key)); k != "" {
+		return k, nil
+	}
+	return "", fmt.Errorf("empty API key file: %s", path)
+}
+
+// maxTokensStops are the stop reasons of a choice cut at the max tokens limit, by provider
+var maxTokensStops = map[string]bool{
+	genai.FinishReasonMaxTokens.String(): true,
+	"length":                             true, // openai
+	"max_tokens":                         true, // anthropic
+}
+
+// isMaxTokensStop reports whether the choice stopped at the max tokens limit
+func isMaxTokensStop(choice *llms.ContentChoice) bool {
+	return maxTokensStops[choice.StopReason]
+}
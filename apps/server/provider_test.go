@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadKey(t *testing.T) {
+	tests := []struct {
+		name string
+		// ctxKey and providerKey are the values of CTX_API_KEY and OPENAI_API_KEY
+		ctxKey, providerKey string
+		// keyFile is the content of the -key-file, homeFile that of ~/.secrets/OPENAI_API_KEY, absent when empty
+		keyFile, homeFile string
+		want              string
+		wantErr           string
+	}{
+		{name: "CTX_API_KEY first", ctxKey: "ctx", providerKey: "env", keyFile: "file", want: "ctx"},
+		{name: "provider env var next", providerKey: "env", keyFile: "file", homeFile: "home", want: "env"},
+		{name: "key file last", keyFile: "file\n", homeFile: "home", want: "file"},
+		{name: "default key file", homeFile: " home \n", want: "home"},
+		{name: "blank env vars", ctxKey: " ", providerKey: "\n", keyFile: "file", want: "file"},
+		{name: "no key", wantErr: "no API key: set CTX_API_KEY or OPENAI_API_KEY"},
+		{name: "empty key file", keyFile: "\n", wantErr: "empty API key file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv(apiKeyEnv, tt.ctxKey)
+			t.Setenv("OPENAI_API_KEY", tt.providerKey)
+
+			if tt.homeFile != "" {
+				writeKey(t, filepath.Join(home, ".secrets", "OPENAI_API_KEY"), tt.homeFile)
+			}
+			keyFile := ""
+			if tt.keyFile != "" {
+				keyFile = filepath.Join(t.TempDir(), "key")
+				writeKey(t, keyFile, tt.keyFile)
+			}
+
+			key, err := providers["openai"].readKey(keyFile)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got %q, %v, want error %q", key, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || key != tt.want {
+				t.Errorf("got %q, %v, want %q", key, err, tt.want)
+			}
+		})
+	}
+}
+
+func writeKey(t *testing.T, path, key string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(key), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseProvider(t *testing.T) {
+	for _, name := range []string{"googleai", "openai", "Anthropic"} {
+		if _, err := parseProvider(name); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if _, err := parseProvider("mistral"); err == nil || !strings.Contains(err.Error(), "anthropic, googleai, openai") {
+		t.Errorf("got %v, want the providers listed", err)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tmc/langchaingo/llms"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// retryBaseDelay is the wait after the first failed generation, doubled after each one
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 30 * time.Second
+)
+
+// retryingModel retries the generations failing with a transient error, see
+// isTransientError, at most attempts times in total
+type retryingModel struct {
+	llm      llms.Model
+	attempts int
+	// after waits between attempts, time.After when nil
+	after func(time.Duration) <-chan time.Time
+}
+
+func (m retryingModel) GenerateContent(ctx context.Context, messages []llms.MessageContent, opts ...llms.CallOption) (*llms.ContentResponse, error) {
+	// a generation that streamed output to the client can't be taken back
+	streamed := false
+	callOpts := llms.CallOptions{}
+	for _, opt := range opts {
+		opt(&callOpts)
+	}
+	if stream := callOpts.StreamingFunc; stream != nil {
+		opts = append(opts[:len(opts):len(opts)], llms.WithStreamingFunc(func(ctx context.Context, chunk []byte) error {
+			streamed = true
+			return stream(ctx, chunk)
+		}))
+	}
+
+	after := m.after
+	if after == nil {
+		after = time.After
+	}
+
+	delay := retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		resp, err := m.llm.GenerateContent(ctx, messages, opts...)
+		if err == nil || attempt >= m.attempts || streamed || !isTransientError(err) {
+			return resp, err
+		}
+
+		wait := jitter(delay)
+		log.Warn().Err(err).Int("attempt", attempt).Dur("backoff", wait).Msg("ai generation failed, retrying")
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-after(wait):
+		}
+
+		delay = min(delay*2, retryMaxDelay)
+	}
+}
+
+func (m retryingModel) Call(ctx context.Context, prompt string, opts ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, opts...)
+}
+
+// jitter returns a random wait between half and all of the delay, so that
+// clients rate limited together don't retry together
+func jitter(delay time.Duration) time.Duration {
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// transientStatus are the HTTP statuses of provider failures that may not happen again
+var transientStatus = map[int]bool{
+	http.StatusRequestTimeout:      true,
+	http.StatusTooManyRequests:     true,
+	http.StatusInternalServerError: true,
+	http.StatusBadGateway:          true,
+	http.StatusServiceUnavailable:  true,
+	http.StatusGatewayTimeout:      true,
+	529:                            true, // anthropic, overloaded
+}
+
+// grpcStatus maps the gRPC codes of googleai failures to their HTTP status
+var grpcStatus = map[codes.Code]int{
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.NotFound:           http.StatusNotFound,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+}
+
+// statusLineRegex matches the errors of the openai and anthropic clients, e.g.
+// "API returned unexpected status code: 503: overloaded"
+var statusLineRegex = regexp.MustCompile(`API returned unexpected status code: (\d{3})\b`)
+
+// statusCode returns the HTTP status of a failed provider request
+func statusCode(err error) (int, bool) {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		code, ok := grpcStatus[grpcErr.GRPCStatus().Code()]
+		return code, ok
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code, true
+	}
+
+	if m := statusLineRegex.FindStringSubmatch(err.Error()); m != nil {
+		code, err := strconv.Atoi(m[1])
+		return code, err == nil
+	}
+	return 0, false
+}
+
+// isTransientError reports whether the generation may succeed if tried again:
+// rate limits, overloaded or unavailable providers and dropped connections.
+// Invalid requests, blocked responses and canceled contexts fail fast.
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if code, ok := statusCode(err); ok {
+		return transientStatus[code]
+	}
+
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+}
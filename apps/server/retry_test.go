@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"googleai unavailable", status.Error(codes.Unavailable, "the model is overloaded"), true},
+		{"googleai quota", status.Error(codes.ResourceExhausted, "quota exceeded"), true},
+		{"googleai internal", status.Error(codes.Internal, "internal error"), true},
+		{"googleai invalid argument", status.Error(codes.InvalidArgument, "request contains an invalid argument"), false},
+		{"googleai permission denied", status.Error(codes.PermissionDenied, "API key not valid"), false},
+		{"googleapi 503", &googleapi.Error{Code: 503}, true},
+		{"googleapi 400", &googleapi.Error{Code: 400}, false},
+		{"openai 429", errors.New("API returned unexpected status code: 429: Rate limit reached"), true},
+		{"openai 500", errors.New("API returned unexpected status code: 500: The server had an error"), true},
+		{"openai 401", errors.New("API returned unexpected status code: 401: Incorrect API key"), false},
+		{"anthropic overloaded", fmt.Errorf("anthropic: failed to create message: %w", errors.New("API returned unexpected status code: 529: Overloaded")), true},
+		{"anthropic 400 mentioning 500", errors.New("API returned unexpected status code: 400: max_tokens: 500000 > 8192"), false},
+		{"path with 500 and eof", errors.New("failed to read apps/500/eof.go"), false},
+		{"token count", errors.New("prompt is 500 tokens over the limit"), false},
+		{"connection closed", &url.Error{Op: "Post", URL: "https://api.openai.com", Err: io.EOF}, true},
+		{"unexpected eof", fmt.Errorf("reading response: %w", io.ErrUnexpectedEOF), true},
+		{"connection reset", &url.Error{Op: "Post", URL: "https://api.openai.com", Err: syscall.ECONNRESET}, true},
+		{"timeout", &url.Error{Op: "Post", URL: "https://api.openai.com", Err: os.ErrDeadlineExceeded}, true},
+		{"deadline", context.DeadlineExceeded, true},
+		{"canceled", context.Canceled, false},
+		{"canceled rpc", fmt.Errorf("generate: %w", context.Canceled), false},
+	}
+	for _, tt := range tests {
+		if got := isTransientError(tt.err); got != tt.want {
+			t.Errorf("%s: isTransientError(%v) = %t, want %t", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestIsRateLimitError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{status.Error(codes.ResourceExhausted, "quota exceeded"), true},
+		{errors.New("API returned unexpected status code: 429: Rate limit reached"), true},
+		{status.Error(codes.Unavailable, "unavailable"), false},
+		{errors.New("line 429 of main.go"), false},
+	}
+	for _, tt := range tests {
+		if got := isRateLimitError(tt.err); got != tt.want {
+			t.Errorf("isRateLimitError(%v) = %t, want %t", tt.err, got, tt.want)
+		}
+	}
+}
+
+// failingModel fails the first fails generations with err
+type failingModel struct {
+	fails int
+	err   error
+	calls *int
+}
+
+func (m failingModel) GenerateContent(context.Context, []llms.MessageContent, ...llms.CallOption) (*llms.ContentResponse, error) {
+	*m.calls++
+	if *m.calls <= m.fails {
+		return nil, m.err
+	}
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "{}"}}}, nil
+}
+
+func (m failingModel) Call(ctx context.Context, prompt string, opts ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, opts...)
+}
+
+// fakeClock records the waits between attempts, which end right away
+type fakeClock struct {
+	waits []time.Duration
+}
+
+func (c *fakeClock) after(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
+
+func TestRetryingModelBacksOff(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "overloaded")
+
+	tests := []struct {
+		name      string
+		fails     int
+		err       error
+		attempts  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{"fails twice then succeeds", 2, unavailable, 3, 3, false},
+		{"gives up after the attempts", 10, unavailable, 7, 7, true},
+		{"fails fast on invalid requests", 2, status.Error(codes.InvalidArgument, "invalid"), 3, 1, true},
+		{"retries disabled", 2, unavailable, 1, 1, true},
+	}
+	for _, tt := range tests {
+		calls := 0
+		clock := &fakeClock{}
+		m := retryingModel{llm: failingModel{fails: tt.fails, err: tt.err, calls: &calls}, attempts: tt.attempts, after: clock.after}
+
+		_, err := m.GenerateContent(context.Background(), nil)
+		if (err != nil) != tt.wantErr || calls != tt.wantCalls {
+			t.Errorf("%s: %d calls, error %v, want %d calls, error %t", tt.name, calls, err, tt.wantCalls, tt.wantErr)
+		}
+
+		// each wait is the doubled delay, capped, less up to half of it in jitter
+		if len(clock.waits) != calls-1 {
+			t.Errorf("%s: %d waits for %d calls", tt.name, len(clock.waits), calls)
+		}
+		delay := retryBaseDelay
+		for i, wait := range clock.waits {
+			if wait < delay/2 || wait > delay {
+				t.Errorf("%s: wait %d is %s, want between %s and %s", tt.name, i, wait, delay/2, delay)
+			}
+			delay = min(delay*2, retryMaxDelay)
+		}
+	}
+}
+
+func TestRetryingModelStopsOnCancel(t *testing.T) {
+	calls := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	m := retryingModel{
+		llm:      failingModel{fails: 5, err: status.Error(codes.Unavailable, "overloaded"), calls: &calls},
+		attempts: 5,
+		// the context is canceled during the first wait, which never ends
+		after: func(time.Duration) <-chan time.Time {
+			cancel()
+			return make(chan time.Time)
+		},
+	}
+
+	if _, err := m.GenerateContent(ctx, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want the context error", err)
+	}
+	if calls != 1 {
+		t.Errorf("%d calls, want 1", calls)
+	}
+}
+
+func TestRetryingModelDoesNotRetryStreamed(t *testing.T) {
+	calls := 0
+	m := retryingModel{llm: streamingFailModel{calls: &calls}, attempts: 3, after: (&fakeClock{}).after}
+
+	_, err := m.GenerateContent(context.Background(), nil, llms.WithStreamingFunc(func(context.Context, []byte) error { return nil }))
+	if err == nil || calls != 1 {
+		t.Errorf("%d calls, error %v, want a single failed call", calls, err)
+	}
+}
+
+// streamingFailModel streams a chunk then fails with a transient error
+type streamingFailModel struct {
+	calls *int
+}
+
+func (m streamingFailModel) GenerateContent(ctx context.Context, _ []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	*m.calls++
+	opts := llms.CallOptions{}
+	for _, o := range options {
+		o(&opts)
+	}
+	if err := opts.StreamingFunc(ctx, []byte("{")); err != nil {
+		return nil, err
+	}
+	return nil, status.Error(codes.Unavailable, "connection dropped")
+}
+
+func (m streamingFailModel) Call(ctx context.Context, prompt string, opts ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, opts...)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/googleai"
+)
+
+// harmThresholds maps the -harm-threshold flag values to the gemini safety thresholds
+var harmThresholds = map[string]googleai.HarmBlockThreshold{
+	"none":   googleai.HarmBlockNone,
+	"high":   googleai.HarmBlockOnlyHigh,
+	"medium": googleai.HarmBlockMediumAndAbove,
+	"low":    googleai.HarmBlockLowAndAbove,
+}
+
+// parseHarmThreshold returns the safety threshold for a flag value
+func parseHarmThreshold(s string) (googleai.HarmBlockThreshold, error) {
+	if t, ok := harmThresholds[strings.ToLower(s)]; ok {
+		return t, nil
+	}
+
+	names := make([]string, 0, len(harmThresholds))
+	for n := range harmThresholds {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	return 0, fmt.Errorf("invalid harm threshold %q, expected one of %s", s, strings.Join(names, ", "))
+}
+
+// blockedReason reports whether the generation was blocked by the safety filters, and why
+func blockedReason(err error) (string, bool) {
+	var blocked *genai.BlockedError
+	if errors.As(err, &blocked) {
+		return blocked.Error(), true
+	}
+	return "", false
+}
+
+// isSafetyStop reports whether any choice of the response was stopped by the safety filters
+func isSafetyStop(resp *llms.ContentResponse) bool {
+	if resp == nil {
+		return false
+	}
+	for _, choice := range resp.Choices {
+		// openai reports its content filter as a stop reason too
+		if choice.StopReason == genai.FinishReasonSafety.String() || choice.StopReason == "content_filter" {
+			return true
+		}
+	}
+	return false
+}
@@ -2,20 +2,24 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
+	ctxtelemetry "github.com/cyber-nic/ctx/libs/telemetry"
 	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	ctxutils "github.com/cyber-nic/ctx/libs/utils"
 	"github.com/gorilla/websocket"
 	"github.com/invopop/jsonschema"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/tmc/langchaingo/llms"
-	"github.com/tmc/langchaingo/llms/googleai"
 )
 
 type CodeContextService interface {
@@ -23,14 +27,38 @@ type CodeContextService interface {
 }
 
 type codeContextService struct {
-	model llms.CallOption
-	llm   *googleai.GoogleAI
+	model     llms.CallOption
+	llm       llms.Model
+	dumps     *debugDumper
+	cache     *contentCache
+	preloads  *preloadCache
+	maxTokens map[ctxtypes.CtxStep]int
+	// maxAdditional caps the additional context files of a selection, 0 disables
+	maxAdditional int
+	budget        *tokenBudget
+	// pingInterval is how often keepalive connections are pinged, 0 disables keepalive
+	pingInterval time.Duration
 }
 
-func NewCodeContextService(llm *googleai.GoogleAI, model string) CodeContextService {
+// NewCodeContextService creates the service. The received context is dumped
+// to debugDumpDir for each client, unless it is empty. maxTokens caps the
+// output of each step, the provider default applies to steps without a cap.
+// maxAdditional caps the additional context files a selection returns.
+// tokenBudget caps the tokens each client can use, 0 disables. Clients asking
+// for keepalive are pinged every pingInterval, 0 disables. Generations failing
+// with a transient error are tried up to generateAttempts times. Uploaded
+// contents are cached up to cacheSize bytes.
+func NewCodeContextService(llm llms.Model, model string, debugDumpDir string, maxTokens map[ctxtypes.CtxStep]int, maxAdditional int, tokenBudget int, pingInterval time.Duration, generateAttempts int, cacheSize int) CodeContextService {
 	return &codeContextService{
-		llm:   llm,
-		model: llms.WithModel(modelName),
+		llm:           retryingModel{llm: llm, attempts: generateAttempts},
+		model:         llms.WithModel(model),
+		dumps:         newDebugDumper(debugDumpDir),
+		cache:         newContentCache(cacheSize),
+		preloads:      newPreloadCache(),
+		maxTokens:     maxTokens,
+		maxAdditional: maxAdditional,
+		budget:        newTokenBudget(tokenBudget),
+		pingInterval:  pingInterval,
 	}
 }
 
@@ -40,42 +68,63 @@ func (wss *codeContextService) Handler(ctx context.Context) func(w http.Response
 	// model.ResponseMIMEType = "application/json"
 
 	return func(w http.ResponseWriter, r *http.Request) {
-		c, err := upgrader.Upgrade(w, r, nil)
+		header := negotiateFeatures(r, wss.pingInterval)
+		c, err := upgrader.Upgrade(w, r, header)
 		if err != nil {
 			log.Err(err).Msg("ws upgrade")
 			return
 		}
 		defer c.Close()
 
+		// reads time out when a keepalive client is gone, others are never pinged
+		keepalive := header.Get(ctxtypes.PingIntervalHeader) != ""
+		if keepalive {
+			ctxutils.KeepAlive(c, wss.pingInterval)
+		}
+
+		// connection logger, each request derives its own logger from it
+		cl := log.With().Str("client_ip", r.RemoteAddr).Str("conn_id", newConnID()).Logger()
+
 		// Set up a close handler
 		c.SetCloseHandler(func(code int, text string) error {
-			log.Info().Int("code", code).Str("text", text).Msg("received close frame")
+			cl.Info().Int("code", code).Str("text", text).Msg("received close frame")
 			message := websocket.FormatCloseMessage(code, "")
 			return c.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
 		})
 
-		l := log.With().Str("client_ip", r.RemoteAddr).Logger()
+		// a large context is loaded in chunks, its tree may be streamed beforehand
+		var chunks contextChunks
+		var tree streamedTree
+
+		for requestID := 1; ; requestID++ {
+			if keepalive {
+				c.SetReadDeadline(time.Now().Add(ctxutils.KeepAliveWait(wss.pingInterval)))
+			}
 
-		for {
 			// block until a message is received
 			mt, message, err := c.ReadMessage()
 			if err != nil {
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					cl.Warn().Msg("client stopped responding to pings")
+					break
+				}
 				if websocket.IsUnexpectedCloseError(err,
 					websocket.CloseNormalClosure,
 					websocket.CloseGoingAway,
 					websocket.CloseAbnormalClosure) {
-					l.Err(err).Msg("unexpected close error")
+					cl.Err(err).Msg("unexpected close error")
 				} else {
-					l.Info().Msg("websocket closed normally")
+					cl.Info().Msg("websocket closed normally")
 				}
 				break
 			}
 
-			log.Info().Int("type", mt).Msg("received message")
+			cl.Info().Int("request_id", requestID).Int("type", mt).Msg("received message")
 
 			// Handle close messages
 			if mt == websocket.CloseMessage {
-				l.Info().Msg("received close message")
+				cl.Info().Int("request_id", requestID).Msg("received close message")
 				continue
 			}
 
@@ -87,204 +136,551 @@ func (wss *codeContextService) Handler(ctx context.Context) func(w http.Response
 			// Unmarshal the message into CtxRequest
 			var req ctxtypes.CtxRequest
 			if err := json.Unmarshal(message, &req); err != nil {
-				l.Err(err).Msg("Error marshalling JSON")
+				cl.Err(err).Int("request_id", requestID).Msg("Error marshalling JSON")
 			}
 
-			// add client id to log
-			l = l.With().Str("client_id", req.ClientID).Str("step", string(req.Step)).Logger()
+			// request logger, derived from the connection logger so fields don't carry over between requests
+			l := cl.With().Int("request_id", requestID).Str("client_id", req.ClientID).Str("step", string(req.Step)).Logger()
 
-			// Marshall the application context
-			jsonCtx, err := json.Marshal(req.Context)
-			// jsonData, err := json.MarshalIndent(req.Context, "", "")
-			if err != nil {
-				l.Err(err).Msg("Failed to marshal JSON")
+			// the assembled context is loaded like a single load request
+			if req.Step == ctxtypes.CtxStepLoadChunk {
+				appCtx, ok := loadChunk(c, l, &chunks, req)
+				if !ok {
+					continue
+				}
+				req.Step = ctxtypes.CtxStepLoadContext
+				req.Context = appCtx
+				req.Chunk = nil
+			}
+
+			// streamed nodes aren't acknowledged, the load request that follows completes the context
+			if req.Step == ctxtypes.CtxStepLoadNodes {
+				tree.add(req.Nodes)
+				l.Trace().Int("nodes", len(req.Nodes)).Int("total", tree.nodes).Msg("tree nodes")
 				continue
 			}
+			if req.Step == ctxtypes.CtxStepLoadContext {
+				if fs := tree.take(); fs != nil {
+					req.Context.FileSystem = fs
+				}
+			}
 
-			// Add the length of the context to the log
-			l = l.With().Int("len", len(jsonCtx)).Logger()
+			wss.handleRequest(ctx, c, mt, l, req)
+		}
+	}
+}
 
-			// Instructions for the AI
-			instructions := []string{}
+// handleRequest processes a request and writes the step response, if any, to the client
+func (wss *codeContextService) handleRequest(ctx context.Context, c *websocket.Conn, mt int, l zerolog.Logger, req ctxtypes.CtxRequest) {
+	// continue the client's trace, if any
+	ctx, span := ctxtelemetry.Tracer().Start(ctxtelemetry.Extract(ctx, req.TraceParent), "handle "+string(req.Step))
+	defer span.End()
+
+	// content uploads don't involve the ai
+	switch req.Step {
+	case ctxtypes.CtxStepManifest:
+		missing := wss.cache.missing(req.Manifest)
+		l.Debug().Int("files", len(req.Manifest)).Int("missing", len(missing)).Msg("manifest")
+		writeJSON(c, ctxtypes.StepManifestResponseSchema{
+			Timestamp: time.Now().Format(time.RFC3339),
+			Step:      string(req.Step),
+			Status:    ctxtypes.StatusOK,
+			Missing:   missing,
+		})
+		return
+	case ctxtypes.CtxStepUpload:
+		stored := wss.cache.store(req.Blobs)
+		l.Debug().Int("blobs", len(req.Blobs)).Int("stored", stored).Msg("upload")
+		writeJSON(c, ctxtypes.StepUploadResponseSchema{
+			Timestamp: time.Now().Format(time.RFC3339),
+			Step:      string(req.Step),
+			Status:    ctxtypes.StatusOK,
+			Stored:    stored,
+		})
+		return
+	}
 
-			switch req.Step {
-			// PRELOAD CONTEXT
-			case ctxtypes.CtxStepLoadContext:
-				schema := GenerateSchema[ctxtypes.StepPreloadResponseSchema]()
-				instructions = []string{
-					"Acknowledge application context and respond step=preload and status=ok",
-					fmt.Sprintf("Respond using this JSON schema: %v", schema),
-				}
+	// restore file contents referenced by hash
+	if unresolved := wss.cache.resolve(&req.Context); len(unresolved) > 0 {
+		l.Warn().Strs("files", unresolved).Msg("file contents not found in cache")
+	}
 
-				// Write the code context to disk
-				go func() {
-					f, err := os.OpenFile(debugCodeContextFile, os.O_CREATE|os.O_WRONLY, 0644)
-					if err != nil {
-						l.Fatal().Err(err).Msgf("Failed to open '%s' file", debugCodeContextFile)
-					}
-					defer f.Close()
+	// the model gets the keywords themselves, not their indexes
+	decoded, err := ctxtypes.DecodeKeywords(req.Context)
+	if err != nil {
+		l.Err(err).Msg("failed to decode keywords")
+		wsErr := websocket.FormatCloseMessage(websocket.CloseInvalidFramePayloadData, "invalid keyword encoding")
+		c.WriteMessage(websocket.CloseMessage, wsErr)
+		return
+	}
+	req.Context = decoded
+
+	// Marshall the application context
+	jsonCtx, err := json.Marshal(req.Context)
+	// jsonData, err := json.MarshalIndent(req.Context, "", "")
+	if err != nil {
+		l.Err(err).Msg("Failed to marshal JSON")
+		return
+	}
 
-					if _, err := f.WriteString(string(jsonCtx)); err != nil {
-						l.Err(err).Msg("Failed to write to file")
-					}
-				}()
+	// hash of the context, only computed for preloads
+	ctxHash := ""
 
-			// SELECT FILES
-			case ctxtypes.CtxStepFileSelection:
-				schema := GenerateSchema[ctxtypes.StepFileSelectFiles]()
+	// Instructions for the AI
+	instructions := buildInstructions(req, wss.maxAdditional)
+	if len(instructions) == 0 {
+		l.Warn().Str("step", string(req.Step)).Msg("unexpected step")
+	}
 
-				instructions = []string{
-					fmt.Sprintf("You are a senior software engineer and system architect. Consider the previously provided application context along with this user prompt describing changes needed to the codebase: ``%s``.", req.UserPrompt),
-					"First identity the list of files that will need to be altered, created or removed in order to implement the requirements or instructions articulated in the prompt. Return these in the `files` array. The `operation` field must be set to 0 for updates, 1 for create, and -1 for remove.",
-					"Next identity additional files for which the content would be useful to have in order to perform the requested changes. Return this list of files in the `additional_context_files` array.",
-					fmt.Sprintf("Respond using this JSON schema: %v", schema),
-				}
+	if req.Step == ctxtypes.CtxStepLoadContext {
+		// skip redundant processing when the context is unchanged since the client's last preload
+		ctxHash = ctxtypes.ContentHash(string(jsonCtx))
+		if ack, ok := wss.preloads.lookup(req.ClientID, ctxHash); ok {
+			l.Debug().Str("status", ack.Status).Msg("context unchanged, using cached preload ack")
+			return
+		}
 
-			// WORK
-			case ctxtypes.CtxStepCodeWork:
-				schema := GenerateSchema[ctxtypes.PatchData]()
+		// Write the code context to disk when debug dumps are enabled
+		wss.dumps.dump(l, req.ClientID, jsonCtx)
+	}
+	l.Debug().Int("len", len(jsonCtx)).Strs("extra_instructions", req.ExtraInstructions).Strs("project_types", req.ProjectTypes).Msg("request")
+
+	// protect the shared key from runaway clients
+	if wss.budget.exceeded(req.ClientID) {
+		b := wss.budget.status(req.ClientID)
+		l.Warn().Int("used", b.Used).Int("limit", b.Limit).Msg("token budget exceeded")
+		wsErr := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, fmt.Sprintf("token budget exceeded: %d of %d tokens used", b.Used, b.Limit))
+		c.WriteMessage(websocket.CloseMessage, wsErr)
+		return
+	}
 
-				instructions = []string{
-					fmt.Sprintf("You are a senior software engineer and system architect. Consider the previously provided application context along with this user prompt describing changes needed to the codebase: ``%s``.", req.UserPrompt),
-					"You always follow best practices and ensure that your code is clean, maintainable, and well-documented. Your code should be production-ready and ready to be reviewed by your peers. Changes are razor-focused and should not include any unrelated changes.",
-					fmt.Sprintf("Respond using a properly formatted git patch, honoring the following schema: %v", schema),
-					fmt.Sprintf("Given the application context and the user prompt, return the changes needed to implement the requirements or instructions articulated in the prompt for the file: \n\n%s", req.WorkPrompt),
-				}
+	// let the client know what is being worked on, preload doesn't expect any message
+	switch req.Step {
+	case ctxtypes.CtxStepPlan:
+		writeStatus(c, "planning", 0)
+	case ctxtypes.CtxStepReview:
+		writeStatus(c, "reviewing", 0)
+	case ctxtypes.CtxStepFileSelection:
+		writeStatus(c, "selecting files", 0)
+	case ctxtypes.CtxStepCodeWork:
+		if req.WorkTarget != nil {
+			writeStatus(c, fmt.Sprintf("generating patch for %s", req.WorkTarget.Path), 0)
+		}
+	}
 
-			// UNEXPECTED
-			default:
-				l.Warn().Str("step", string(req.Step)).Msg("unexpected step")
-			}
-			l.Debug().Msg("request")
+	promptParts, err := formatGenaiParts(string(jsonCtx), instructions)
+	if err != nil {
+		l.Err(err).Msg("unexpected error")
+		return
+	}
 
-			promptParts, err := formatGenaiParts(string(jsonCtx), instructions)
-			if err != nil {
-				l.Err(err).Msg("unexpected error")
-				continue
-			}
+	content := []llms.MessageContent{
+		{
+			Role:  llms.ChatMessageTypeHuman,
+			Parts: promptParts,
+		},
+	}
 
-			content := []llms.MessageContent{
-				{
-					Role:  llms.ChatMessageTypeHuman,
-					Parts: promptParts,
-				},
-			}
+	// continuations extend a partial document, json mode would have the model start a new one
+	genOpts := []llms.CallOption{wss.model, llms.WithTemperature(0.8)}
+	if n := wss.maxTokens[req.Step]; n > 0 {
+		genOpts = append(genOpts, llms.WithMaxTokens(n))
+	}
+	baseOpts := append(genOpts[:len(genOpts):len(genOpts)], llms.WithJSONMode())
+	opts := baseOpts
+	if req.Step == ctxtypes.CtxStepCodeWork && req.Candidates > 1 {
+		opts = append(opts, llms.WithCandidateCount(req.Candidates))
+	}
+	// only the first generation is streamed, continuations and repairs complete it in the step response
+	if req.Step == ctxtypes.CtxStepCodeWork && req.Stream && req.Candidates <= 1 {
+		opts = append(opts, llms.WithStreamingFunc(func(_ context.Context, chunk []byte) error {
+			return writeChunk(c, chunk)
+		}))
+	}
 
-			start := time.Now()
-			aiResp, err := wss.llm.GenerateContent(ctx, content, wss.model, llms.WithTemperature(0.8), llms.WithJSONMode())
+	// generations are charged to the client's budget
+	llm := meteredModel{llm: wss.llm, budget: wss.budget, clientID: req.ClientID}
 
-			if err != nil {
-				l.Error().Err(err).Msg("ai failed to generate content") // Changed from Fatal to Error
-				wsErr := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "ai generation failed")
-				c.WriteMessage(websocket.CloseMessage, wsErr)
-				continue
-			}
+	start := time.Now()
+	aiResp, err := llm.GenerateContent(ctx, content, opts...)
 
-			// Log the elapsed time
-			elapsed := time.Since(start)
-			l = l.With().Int64("elapsed_ms", elapsed.Milliseconds()).Logger()
+	if err != nil && isRateLimitError(err) && req.Step == ctxtypes.CtxStepCodeWork {
+		// let the client back off and retry rather than tearing down the session
+		l.Warn().Err(err).Msg("ai rate limited")
+		d, _ := json.Marshal(ctxtypes.StepFileWorkResponseSchema{
+			Timestamp: time.Now().Format(time.RFC3339),
+			Step:      string(req.Step),
+			Status:    ctxtypes.StatusRateLimited,
+		})
+		if err := c.WriteMessage(mt, d); err != nil {
+			l.Err(err).Msg("failed to write message to ws")
+		}
+		return
+	}
 
-			data, err := extractResponseContent(aiResp)
-			if err != nil {
-				l.Err(err).Msg("failed to extract ai response content")
+	if reason, ok := blockedReason(err); ok {
+		l.Warn().Str("reason", reason).Msg("ai response blocked by safety filters, see -harm-threshold")
+		wsErr := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "ai response blocked by safety filters")
+		c.WriteMessage(websocket.CloseMessage, wsErr)
+		return
+	}
 
-				// preload doesn't expect a response
-				if req.Step != ctxtypes.CtxStepLoadContext {
-					wsErr := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "failed to extract response")
-					c.WriteMessage(websocket.CloseMessage, wsErr)
-				}
-				continue
-			}
+	if err != nil {
+		l.Error().Err(err).Msg("ai failed to generate content") // Changed from Fatal to Error
+		wsErr := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "ai generation failed")
+		c.WriteMessage(websocket.CloseMessage, wsErr)
+		return
+	}
 
-			// ndelorme - unmarshal into step corresponding response model
-			switch req.Step {
-			case ctxtypes.CtxStepLoadContext:
-				// unmarshal data into StepPreloadResponseSchema
-				respData := ctxtypes.StepPreloadResponseSchema{}
+	// complete responses cut off by the max tokens limit
+	completeTruncated(ctx, llm, l, content, aiResp, genOpts...)
 
-				if err := json.Unmarshal([]byte(data), &respData); err != nil {
-					l.Err(err).Msg("failed to unmarshal preload ack response")
-					continue
-				}
-				l.Debug().Str("status", respData.Status).Msg("response")
+	// Log the elapsed time
+	l.Debug().Int64("elapsed_ms", time.Since(start).Milliseconds()).Msg("ai responded")
 
-				// log preload ack to stdout
-				continue
-			case ctxtypes.CtxStepFileSelection:
-				// unmarshal data into StepPreloadResponseSchema
-				fileData := ctxtypes.StepFileSelectFiles{}
+	data, err := extractResponseContent(aiResp)
+	if err != nil {
+		l.Err(err).Msg("failed to extract ai response content")
 
-				if err := json.Unmarshal([]byte(data), &fileData); err != nil {
-					l.Err(err).Msg("failed to unmarshal preload ack response")
-					continue
-				}
-				l.Debug().Str("status", "ok").Msg("response")
+		// preload doesn't expect a response
+		if req.Step != ctxtypes.CtxStepLoadContext {
+			wsErr := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "failed to extract response")
+			c.WriteMessage(websocket.CloseMessage, wsErr)
+		}
+		return
+	}
 
-				respData := ctxtypes.StepFileSelectResponseSchema{
-					Timestamp: time.Now().Format(time.RFC3339),
-					Step:      string(req.Step),
-					Status:    "ok",
-					Data:      fileData,
-				}
+	// validate against the schema asked for, giving the model a chance to fix its response
+	schema := responseSchema(req)
+	data, err = repairResponse(ctx, llm, l, content, schema, data, baseOpts...)
+	if err != nil {
+		l.Err(err).Msg("ai response doesn't match the schema")
 
-				// marshal response
-				d, err := json.Marshal(respData)
-				if err != nil {
-					l.Err(err).Msg("failed to marshal response")
-					continue
-				}
+		// preload doesn't expect a response
+		if req.Step != ctxtypes.CtxStepLoadContext {
+			wsErr := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "invalid response")
+			c.WriteMessage(websocket.CloseMessage, wsErr)
+		}
+		return
+	}
+	// candidates are read from the choices, the first one is the validated response
+	if len(aiResp.Choices) > 0 {
+		aiResp.Choices[0].Content = data
+	}
 
-				// preload doesn't expect a response
-				if err = c.WriteMessage(mt, []byte(d)); err != nil {
-					l.Err(err).Msg("failed to write message to ws")
-					continue
-				}
+	// ndelorme - unmarshal into step corresponding response model
+	switch req.Step {
+	case ctxtypes.CtxStepLoadContext:
+		// unmarshal data into StepPreloadResponseSchema
+		respData := ctxtypes.StepPreloadResponseSchema{}
+
+		if err := json.Unmarshal([]byte(data), &respData); err != nil {
+			l.Err(err).Msg("failed to unmarshal preload ack response")
+			return
+		}
+		l.Debug().Str("status", respData.Status).Msg("response")
+		wss.preloads.store(req.ClientID, ctxHash, respData)
+
+		// log preload ack to stdout
+		return
+	case ctxtypes.CtxStepPlan:
+		planData := ctxtypes.StepPlanData{}
+		if err := json.Unmarshal([]byte(data), &planData); err != nil {
+			l.Err(err).Msg("failed to unmarshal plan response")
+			wsErr := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "invalid plan response")
+			c.WriteMessage(websocket.CloseMessage, wsErr)
+			return
+		}
+		l.Debug().Str("status", "ok").Int("steps", len(planData.Steps)).Msg("response")
+
+		writeJSON(c, ctxtypes.StepPlanResponseSchema{
+			Timestamp: time.Now().Format(time.RFC3339),
+			Step:      string(req.Step),
+			Status:    ctxtypes.StatusOK,
+			Data:      planData,
+			Budget:    wss.budget.status(req.ClientID),
+		})
+	case ctxtypes.CtxStepReview:
+		reviewData := ctxtypes.StepReviewData{}
+		if err := json.Unmarshal([]byte(data), &reviewData); err != nil {
+			l.Err(err).Msg("failed to unmarshal review response")
+			wsErr := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "invalid review response")
+			c.WriteMessage(websocket.CloseMessage, wsErr)
+			return
+		}
+		l.Debug().Str("status", "ok").Int("comments", len(reviewData.Comments)).Msg("response")
+
+		writeJSON(c, ctxtypes.StepReviewResponseSchema{
+			Timestamp: time.Now().Format(time.RFC3339),
+			Step:      string(req.Step),
+			Status:    ctxtypes.StatusOK,
+			Data:      reviewData,
+			Budget:    wss.budget.status(req.ClientID),
+		})
+	case ctxtypes.CtxStepFileSelection:
+		// unmarshal data into StepPreloadResponseSchema
+		fileData := ctxtypes.StepFileSelectFiles{}
 
-			case ctxtypes.CtxStepCodeWork:
-				// unmarshal data into PatchData
-				patchData := ctxtypes.PatchData{}
+		if err := json.Unmarshal([]byte(data), &fileData); err != nil {
+			l.Err(err).Msg("failed to unmarshal preload ack response")
+			return
+		}
+		if n := wss.maxAdditional; n > 0 && len(fileData.Additional) > n {
+			l.Warn().Int("returned", len(fileData.Additional)).Int("max", n).Msg("truncating additional context files")
+			fileData.Additional = fileData.Additional[:n]
+		}
+		l.Debug().Str("status", "ok").Msg("response")
+
+		respData := ctxtypes.StepFileSelectResponseSchema{
+			Timestamp: time.Now().Format(time.RFC3339),
+			Step:      string(req.Step),
+			Status:    "ok",
+			Data:      fileData,
+			Budget:    wss.budget.status(req.ClientID),
+		}
 
-				fmt.Println(data)
+		// marshal response
+		d, err := json.Marshal(respData)
+		if err != nil {
+			l.Err(err).Msg("failed to marshal response")
+			return
+		}
 
-				if err := json.Unmarshal([]byte(data), &patchData); err != nil {
-					l.Err(err).Msg("failed to unmarshal git patch response")
-					continue
-				}
-				l.Debug().Str("status", "ok").Msg("response")
+		// preload doesn't expect a response
+		if err = c.WriteMessage(mt, []byte(d)); err != nil {
+			l.Err(err).Msg("failed to write message to ws")
+			return
+		}
 
-				respData := ctxtypes.StepFileWorkResponseSchema{
-					Timestamp: time.Now().Format(time.RFC3339),
-					Step:      string(req.Step),
-					Status:    "ok",
-					Data:      patchData,
-				}
+	case ctxtypes.CtxStepCodeWork:
+		// edits are returned as is, candidates and tests only apply to patches
+		if req.WorkFormat == ctxtypes.WorkFormatEdits {
+			editData := ctxtypes.EditData{}
+			if err := json.Unmarshal([]byte(data), &editData); err != nil {
+				l.Err(err).Msg("failed to unmarshal edits response")
+				wsErr := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "invalid edits response")
+				c.WriteMessage(websocket.CloseMessage, wsErr)
+				return
+			}
+			l.Debug().Str("status", "ok").Int("edits", len(editData.Edits)).Msg("response")
+
+			writeJSON(c, ctxtypes.StepFileWorkResponseSchema{
+				Timestamp: time.Now().Format(time.RFC3339),
+				Step:      string(req.Step),
+				Status:    ctxtypes.StatusOK,
+				Edits:     editData.Edits,
+				Budget:    wss.budget.status(req.ClientID),
+			})
+			return
+		}
 
-				// marshal response
-				d, err := json.Marshal(respData)
-				if err != nil {
-					l.Err(err).Msg("failed to marshal response")
-					continue
-				}
+		choices := extractResponseChoices(aiResp)
 
-				// preload doesn't expect a response
-				if err = c.WriteMessage(mt, []byte(d)); err != nil {
-					l.Err(err).Msg("failed to write message to ws")
-					continue
-				}
+		// not every provider honors the candidate count, fill the gap with repeated generations
+		for i := len(choices); i < req.Candidates; i++ {
+			more, err := llm.GenerateContent(ctx, content, baseOpts...)
+			if err != nil {
+				l.Warn().Err(err).Int("candidate", i).Msg("ai failed to generate candidate")
+				break
+			}
+			completeTruncated(ctx, llm, l, content, more, genOpts...)
+			choices = append(choices, extractResponseChoices(more)...)
+		}
 
+		// unmarshal each choice, test patches are kept from the first valid one
+		patches := []ctxtypes.PatchData{}
+		tests := []ctxtypes.PatchData{}
+		for i, choice := range choices {
+			if err := validateResponse(schema, choice); err != nil {
+				l.Warn().Err(err).Int("candidate", i).Msg("ai candidate doesn't match the schema")
+				continue
+			}
+			patchData := ctxtypes.PatchDataWithTests{}
+			if err := json.Unmarshal([]byte(choice), &patchData); err != nil {
+				l.Err(err).Msg("failed to unmarshal git patch response")
+				continue
+			}
+			if len(patches) == 0 && req.WithTests {
+				for _, t := range patchData.Tests {
+					if t.Path == "" || t.Patch == "" {
+						continue
+					}
+					t.IsTest = true
+					tests = append(tests, t)
+				}
 			}
+			patches = append(patches, ctxtypes.PatchData{Patch: patchData.Patch, Confidence: patchData.Confidence, Assumptions: patchData.Assumptions})
+		}
+
+		if len(patches) == 0 {
+			l.Error().Msg("no valid git patch in response")
+			wsErr := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "no valid patch")
+			c.WriteMessage(websocket.CloseMessage, wsErr)
+			return
+		}
+		l.Debug().Str("status", "ok").Int("candidates", len(patches)).Msg("response")
+
+		respData := ctxtypes.StepFileWorkResponseSchema{
+			Timestamp: time.Now().Format(time.RFC3339),
+			Step:      string(req.Step),
+			Status:    "ok",
+			Data:      patches[0],
+			Budget:    wss.budget.status(req.ClientID),
+		}
+		if req.Candidates > 1 {
+			respData.Candidates = patches
+		}
+		if len(tests) > 0 {
+			respData.Tests = tests
+		}
 
+		// marshal response
+		d, err := json.Marshal(respData)
+		if err != nil {
+			l.Err(err).Msg("failed to marshal response")
+			return
+		}
+
+		// preload doesn't expect a response
+		if err = c.WriteMessage(mt, []byte(d)); err != nil {
+			l.Err(err).Msg("failed to write message to ws")
+			return
+		}
+
+	}
+}
+
+// supportedFeatures are the optional protocol features the server implements
+var supportedFeatures = map[string]bool{
+	ctxtypes.FeatureInternedKeywords: true,
+	ctxtypes.FeatureChunkedLoad:      true,
+	ctxtypes.FeatureStreamedLoad:     true,
+}
+
+// negotiateFeatures returns the upgrade response headers listing the features
+// requested by the client that the server supports, along with the ping
+// interval when keepalive is accepted
+func negotiateFeatures(r *http.Request, pingInterval time.Duration) http.Header {
+	accepted := []string{}
+	header := http.Header{}
+	for _, value := range r.Header.Values(ctxtypes.FeatureHeader) {
+		for _, feature := range strings.Split(value, ",") {
+			feature = strings.TrimSpace(feature)
+			switch {
+			case supportedFeatures[feature]:
+				accepted = append(accepted, feature)
+			case feature == ctxtypes.FeatureKeepalive && pingInterval > 0:
+				accepted = append(accepted, feature)
+				header.Set(ctxtypes.PingIntervalHeader, pingInterval.String())
+			}
 		}
 	}
+	if len(accepted) == 0 {
+		return nil
+	}
+	header.Set(ctxtypes.FeatureHeader, strings.Join(accepted, ","))
+	return header
 }
 
+// newConnID returns a short random identifier to correlate the logs of a connection
+func newConnID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// extractResponseContent returns the content of the first choice. Choices are
+// alternatives rather than parts, so concatenating them would produce invalid JSON.
 func extractResponseContent(resp *llms.ContentResponse) (string, error) {
-	builder := strings.Builder{}
+	choices := extractResponseChoices(resp)
+	if len(choices) == 0 {
+		if isSafetyStop(resp) {
+			return "", errors.New("ai response blocked by safety filters")
+		}
+		if isTruncated(resp) {
+			return "", errors.New("ai response truncated at the max tokens limit")
+		}
+		return "", errors.New("ai response has no content")
+	}
+	return choices[0], nil
+}
+
+// extractResponseChoices returns the non-empty content of every complete
+// choice. Truncated choices are dropped since partial JSON can't be parsed.
+func extractResponseChoices(resp *llms.ContentResponse) []string {
+	choices := []string{}
+	if resp == nil {
+		return choices
+	}
 
 	for _, choice := range resp.Choices {
-		builder.Write([]byte(fmt.Sprintf("%s\n", choice.Content)))
+		if isMaxTokensStop(choice) {
+			log.Warn().Int("len", len(choice.Content)).Msg("dropping ai response choice truncated at the max tokens limit")
+			continue
+		}
+		if c := strings.TrimSpace(choice.Content); c != "" {
+			choices = append(choices, c)
+		}
+	}
+	return choices
+}
+
+// isTruncated reports whether any choice of the response hit the max tokens limit
+func isTruncated(resp *llms.ContentResponse) bool {
+	if resp == nil {
+		return false
+	}
+	for _, choice := range resp.Choices {
+		if isMaxTokensStop(choice) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeJSON marshals and sends a response, logging failures
+func writeJSON(c *websocket.Conn, v any) {
+	d, err := json.Marshal(v)
+	if err != nil {
+		log.Err(err).Msg("failed to marshal response")
+		return
+	}
+
+	if err := c.WriteMessage(websocket.TextMessage, d); err != nil {
+		log.Err(err).Msg("failed to write message to ws")
+	}
+}
+
+// writeStatus sends a progress message to the client. Failures are only logged
+// since the step response that follows will surface a broken connection.
+func writeStatus(c *websocket.Conn, phase string, percent int) {
+	writeJSON(c, ctxtypes.StepStatusResponseSchema{
+		Timestamp: time.Now().Format(time.RFC3339),
+		Step:      string(ctxtypes.CtxStepStatus),
+		Data:      ctxtypes.StepStatusData{Phase: phase, Percent: percent},
+	})
+}
+
+// writeChunk sends a piece of the work step output to the client. A failure
+// stops the generation, the client is gone.
+func writeChunk(c *websocket.Conn, chunk []byte) error {
+	d, err := json.Marshal(ctxtypes.StepWorkChunkResponseSchema{
+		Timestamp: time.Now().Format(time.RFC3339),
+		Step:      string(ctxtypes.CtxStepWorkChunk),
+		Chunk:     string(chunk),
+	})
+	if err != nil {
+		return err
 	}
-	return builder.String(), nil
+	return c.WriteMessage(websocket.TextMessage, d)
+}
+
+// isRateLimitError reports whether the provider rejected the request due to rate limits or quota
+func isRateLimitError(err error) bool {
+	code, ok := statusCode(err)
+	return ok && code == http.StatusTooManyRequests
 }
 
 func formatGenaiParts(codeCtx string, instructions []string) ([]llms.ContentPart, error) {
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// streamingModel generates content, streaming it in pieces of chunkSize bytes when asked to
+type streamingModel struct {
+	content   string
+	chunkSize int
+}
+
+func (m streamingModel) GenerateContent(ctx context.Context, _ []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	opts := llms.CallOptions{}
+	for _, o := range options {
+		o(&opts)
+	}
+	if opts.StreamingFunc != nil {
+		for rest := m.content; rest != ""; {
+			n := min(m.chunkSize, len(rest))
+			if err := opts.StreamingFunc(ctx, []byte(rest[:n])); err != nil {
+				return nil, err
+			}
+			rest = rest[n:]
+		}
+	}
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: m.content, StopReason: "stop"}}}, nil
+}
+
+func (m streamingModel) Call(ctx context.Context, prompt string, opts ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, opts...)
+}
+
+// newTestService serves the service backed by llm, and returns its websocket address
+func newTestService(t *testing.T, llm llms.Model) string {
+	t.Helper()
+	svc := NewCodeContextService(llm, "test-model", "", nil, 0, 0, 0, 1, 1<<20)
+	srv := httptest.NewServer(http.HandlerFunc(svc.Handler(context.Background())))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+// workFrames sends the work request and returns the steps of the frames
+// received up to the work response, and the streamed output
+func workFrames(t *testing.T, addr string, req ctxtypes.CtxRequest) (steps []string, streamed string, resp ctxtypes.StepFileWorkResponseSchema) {
+	t.Helper()
+	c, _, err := websocket.DefaultDialer.Dial(addr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	if err := c.WriteJSON(req); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		_, message, err := c.ReadMessage()
+		if err != nil {
+			t.Fatalf("read after %q: %v", steps, err)
+		}
+		var chunk ctxtypes.StepWorkChunkResponseSchema
+		if err := json.Unmarshal(message, &chunk); err != nil {
+			t.Fatal(err)
+		}
+		steps = append(steps, chunk.Step)
+
+		switch ctxtypes.CtxStep(chunk.Step) {
+		case ctxtypes.CtxStepWorkChunk:
+			streamed += chunk.Chunk
+		case ctxtypes.CtxStepCodeWork:
+			if err := json.Unmarshal(message, &resp); err != nil {
+				t.Fatal(err)
+			}
+			return steps, streamed, resp
+		}
+	}
+}
+
+func TestHandlerStreamsWork(t *testing.T) {
+	content := `{"patch":"@@ -1 +1 @@\n-package a\n+package b\n","confidence":0.9}`
+	addr := newTestService(t, streamingModel{content: content, chunkSize: 8})
+
+	tests := []struct {
+		name       string
+		stream     bool
+		candidates int
+		wantChunks bool
+	}{
+		{"streamed", true, 0, true},
+		{"not asked for", false, 0, false},
+		{"candidates", true, 2, false},
+	}
+	for _, tt := range tests {
+		req := ctxtypes.CtxRequest{
+			ClientID:   "client",
+			Step:       ctxtypes.CtxStepCodeWork,
+			UserPrompt: "rename the package",
+			WorkTarget: &ctxtypes.WorkTarget{Path: "a.go", Content: "package a\n"},
+			Stream:     tt.stream,
+			Candidates: tt.candidates,
+		}
+		steps, streamed, resp := workFrames(t, addr, req)
+
+		chunks := 0
+		for _, step := range steps {
+			if step == string(ctxtypes.CtxStepWorkChunk) {
+				chunks++
+			}
+		}
+		if !tt.wantChunks {
+			if chunks > 0 {
+				t.Errorf("%s: got %d chunks", tt.name, chunks)
+			}
+			continue
+		}
+
+		// every chunk is received before the work response, which marks the end of the stream
+		if want := (len(content) + 7) / 8; chunks != want {
+			t.Errorf("%s: got %d chunks in %q, want %d", tt.name, chunks, steps, want)
+		}
+		if last := steps[len(steps)-1]; last != string(ctxtypes.CtxStepCodeWork) {
+			t.Errorf("%s: last frame is %q", tt.name, last)
+		}
+		if streamed != content {
+			t.Errorf("%s: streamed %q, want %q", tt.name, streamed, content)
+		}
+		if resp.Status != ctxtypes.StatusOK || !strings.Contains(resp.Data.Patch, "+package b") {
+			t.Errorf("%s: got response %+v", tt.name, resp)
+		}
+	}
+}
+
+// logBuffer collects the log lines written by the handlers
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// lines returns the log lines written so far
+func (b *logBuffer) lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.Split(strings.TrimSpace(b.buf.String()), "\n")
+}
+
+func TestHandlerRequestLoggers(t *testing.T) {
+	logs := &logBuffer{}
+	logger := log.Logger
+	log.Logger = zerolog.New(logs).Level(zerolog.DebugLevel)
+	t.Cleanup(func() { log.Logger = logger })
+
+	addr := newTestService(t, streamingModel{content: `{"summary":"one step","steps":[]}`})
+	c, _, err := websocket.DefaultDialer.Dial(addr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	// two requests of different clients and steps on the same connection
+	requests := []ctxtypes.CtxRequest{
+		{ClientID: "first", Step: ctxtypes.CtxStepManifest, Manifest: map[string]string{"a.go": "h"}},
+		{ClientID: "second", Step: ctxtypes.CtxStepPlan, UserPrompt: "plan it"},
+	}
+	for _, req := range requests {
+		if err := c.WriteJSON(req); err != nil {
+			t.Fatal(err)
+		}
+		for {
+			var resp ctxtypes.StepStatusResponseSchema
+			if err := c.ReadJSON(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Step == string(req.Step) {
+				break
+			}
+		}
+	}
+
+	byRequest := map[float64][]map[string]any{}
+	for _, line := range logs.lines() {
+		// a field carried over would be written again by the next request's logger
+		for _, key := range []string{"request_id", "client_id", "step", "conn_id", "files", "len"} {
+			if n := strings.Count(line, `"`+key+`":`); n > 1 {
+				t.Errorf("%s written %d times in %s", key, n, line)
+			}
+		}
+
+		var fields map[string]any
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := fields["client_id"]; ok {
+			id, _ := fields["request_id"].(float64)
+			byRequest[id] = append(byRequest[id], fields)
+		}
+	}
+
+	for i, req := range requests {
+		entries := byRequest[float64(i+1)]
+		if len(entries) == 0 {
+			t.Fatalf("no logs of request %d in %q", i+1, logs.lines())
+		}
+		for _, fields := range entries {
+			if fields["client_id"] != req.ClientID || fields["step"] != string(req.Step) {
+				t.Errorf("request %d logged %v", i+1, fields)
+			}
+		}
+	}
+	for _, fields := range byRequest[2] {
+		if _, ok := fields["files"]; ok {
+			t.Errorf("the manifest fields leaked into %v", fields)
+		}
+	}
+}
+
+func TestHandlerWorkConfidence(t *testing.T) {
+	content := `{"patch":"@@ -1 +1 @@\n-package a\n+package b\n","confidence":0.65,"assumptions":["nothing imports a"]}`
+	addr := newTestService(t, streamingModel{content: content})
+
+	for _, candidates := range []int{0, 2} {
+		req := ctxtypes.CtxRequest{
+			ClientID:   "client",
+			Step:       ctxtypes.CtxStepCodeWork,
+			UserPrompt: "rename the package",
+			WorkTarget: &ctxtypes.WorkTarget{Path: "a.go", Content: "package a\n"},
+			Candidates: candidates,
+		}
+		_, _, resp := workFrames(t, addr, req)
+
+		// the assessment is forwarded with the patch and each candidate
+		patches := append([]ctxtypes.PatchData{resp.Data}, resp.Candidates...)
+		if len(patches) != 1+candidates {
+			t.Fatalf("%d candidates: got %d patches", candidates, len(patches))
+		}
+		for i, p := range patches {
+			if p.Confidence == nil || *p.Confidence != 0.65 || len(p.Assumptions) != 1 || p.Assumptions[0] != "nothing imports a" {
+				t.Errorf("%d candidates: patch %d is %+v", candidates, i, p)
+			}
+		}
+	}
+}
+
+// scriptedModel returns its responses in turn, failing on a nil one and once they run out
+type scriptedModel struct {
+	mu        sync.Mutex
+	responses []*llms.ContentResponse
+}
+
+func (m *scriptedModel) GenerateContent(context.Context, []llms.MessageContent, ...llms.CallOption) (*llms.ContentResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.responses) == 0 {
+		return nil, errors.New("no more responses")
+	}
+	resp := m.responses[0]
+	m.responses = m.responses[1:]
+	if resp == nil {
+		return nil, errors.New("scripted failure")
+	}
+	return resp, nil
+}
+
+func (m *scriptedModel) Call(ctx context.Context, prompt string, opts ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, opts...)
+}
+
+// closeError sends the request and reads up to the close frame of the server,
+// failing on any response
+func closeError(t *testing.T, addr string, req ctxtypes.CtxRequest) *websocket.CloseError {
+	t.Helper()
+	c, _, err := websocket.DefaultDialer.Dial(addr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	if err := c.WriteJSON(req); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		var resp ctxtypes.StepStatusResponseSchema
+		err := c.ReadJSON(&resp)
+		var closeErr *websocket.CloseError
+		if errors.As(err, &closeErr) {
+			return closeErr
+		}
+		if err != nil {
+			t.Fatalf("got %v, want a close frame", err)
+		}
+		if resp.Step == string(req.Step) {
+			t.Fatalf("got a %s response, want a close frame", resp.Step)
+		}
+	}
+}
+
+func TestHandlerWorkNoValidPatch(t *testing.T) {
+	// the truncated candidate fails to be continued. The other one has an
+	// unknown property, its repair replaces the content of the truncated one.
+	llm := &scriptedModel{responses: []*llms.ContentResponse{
+		{Choices: []*llms.ContentChoice{
+			{Content: `{"patch":"@@ -1`, StopReason: "length"},
+			{Content: `{"patch":"@@ -1 +1 @@\n-package a\n+package b\n","path":"a.go","unknown":1}`, StopReason: "stop"},
+		}},
+		nil,
+		{Choices: []*llms.ContentChoice{{Content: `{"patch":"@@ -1 +1 @@\n-package a\n+package b\n"}`, StopReason: "stop"}}},
+	}}
+	addr := newTestService(t, llm)
+
+	closeErr := closeError(t, addr, ctxtypes.CtxRequest{
+		ClientID:   "client",
+		Step:       ctxtypes.CtxStepCodeWork,
+		UserPrompt: "rename the package",
+		WorkTarget: &ctxtypes.WorkTarget{Path: "a.go", Content: "package a\n"},
+	})
+	if closeErr.Code != websocket.CloseInternalServerErr || closeErr.Text != "no valid patch" {
+		t.Errorf("got %v, want the no valid patch close frame", closeErr)
+	}
+}
+
+func TestHandlerWorkInvalidEdits(t *testing.T) {
+	// a line of 1.0 is an integer to the schema, not to encoding/json
+	content := `{"edits":[{"range":{"start":{"line":1.0,"character":0},"end":{"line":1,"character":0}},"newText":"// a\n"}]}`
+	addr := newTestService(t, streamingModel{content: content})
+
+	closeErr := closeError(t, addr, ctxtypes.CtxRequest{
+		ClientID:   "client",
+		Step:       ctxtypes.CtxStepCodeWork,
+		UserPrompt: "document the package",
+		WorkTarget: &ctxtypes.WorkTarget{Path: "a.go", Content: "package a\n"},
+		WorkFormat: ctxtypes.WorkFormatEdits,
+	})
+	if closeErr.Code != websocket.CloseInternalServerErr || closeErr.Text != "invalid edits response" {
+		t.Errorf("got %v, want the invalid edits close frame", closeErr)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	ctxtypes "github.com/cyber-nic/ctx/libs/types"
+)
+
+// streamedTree assembles the file system tree streamed over a connection in
+// batches of nodes, until the load request it belongs to
+type streamedTree struct {
+	root  *ctxtypes.FileSystemNode
+	nodes int
+}
+
+// add inserts the nodes at their path, creating the missing parent directories.
+// A directory keeps the children already received.
+func (t *streamedTree) add(nodes map[string]ctxtypes.FileSystemNode) {
+	if t.root == nil {
+		t.root = &ctxtypes.FileSystemNode{Directory: true, Children: map[string]*ctxtypes.FileSystemNode{}}
+	}
+
+	// parents first, within the batch
+	paths := make([]string, 0, len(nodes))
+	for p := range nodes {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		parts := strings.Split(strings.Trim(p, "/"), "/")
+		parent := t.root
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := parent.Children[part]
+			if !ok || child.Children == nil {
+				child = &ctxtypes.FileSystemNode{Directory: true, Children: map[string]*ctxtypes.FileSystemNode{}}
+				parent.Children[part] = child
+			}
+			parent = child
+		}
+
+		name := parts[len(parts)-1]
+		n := nodes[p]
+		if existing, ok := parent.Children[name]; ok && existing.Children != nil {
+			n.Children = existing.Children
+		} else if n.Directory && !n.Skip {
+			n.Children = map[string]*ctxtypes.FileSystemNode{}
+		}
+		parent.Children[name] = &n
+		t.nodes++
+	}
+}
+
+// take returns the assembled tree, nil when none was streamed, and resets it
+func (t *streamedTree) take() map[string]ctxtypes.FileSystemNode {
+	if t.root == nil {
+		return nil
+	}
+	tree := map[string]ctxtypes.FileSystemNode{ctxtypes.RootKey: *t.root}
+	t.root = nil
+	t.nodes = 0
+	return tree
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"github.com/tmc/langchaingo/llms"
+)
+
+const repairPrompt = "Your previous response does not match the requested JSON schema: %s. Respond again with the complete, corrected JSON document only, without any preamble."
+
+// compiledSchemas caches the validators by schema document
+var compiledSchemas sync.Map
+
+// compileSchema returns the validator of the schema document
+func compileSchema(schema string) (*jsonschema.Schema, error) {
+	if v, ok := compiledSchemas.Load(schema); ok {
+		return v.(*jsonschema.Schema), nil
+	}
+
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource("response.json", strings.NewReader(schema)); err != nil {
+		return nil, err
+	}
+	compiled, err := c.Compile("response.json")
+	if err != nil {
+		return nil, err
+	}
+
+	compiledSchemas.Store(schema, compiled)
+	return compiled, nil
+}
+
+// validateResponse checks the model output against the schema it was asked to respond with
+func validateResponse(schema interface{}, data string) error {
+	doc, ok := schema.(string)
+	if !ok || doc == "" {
+		return nil
+	}
+
+	compiled, err := compileSchema(doc)
+	if err != nil {
+		return fmt.Errorf("invalid response schema: %w", err)
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		return fmt.Errorf("not a JSON document: %w", err)
+	}
+
+	return compiled.Validate(v)
+}
+
+// repairResponse validates data against the schema and, when invalid, asks the
+// model once to correct its response. It returns the valid response, or the
+// last validation error.
+func repairResponse(ctx context.Context, llm llms.Model, l zerolog.Logger, content []llms.MessageContent, schema interface{}, data string, opts ...llms.CallOption) (string, error) {
+	verr := validateResponse(schema, data)
+	if verr == nil {
+		return data, nil
+	}
+	l.Warn().Err(verr).Msg("ai response doesn't match the schema, asking for a repair")
+
+	messages := append(append([]llms.MessageContent{}, content...),
+		llms.TextParts(llms.ChatMessageTypeAI, data),
+		llms.TextParts(llms.ChatMessageTypeHuman, fmt.Sprintf(repairPrompt, verr)),
+	)
+
+	resp, err := llm.GenerateContent(ctx, messages, opts...)
+	if err != nil {
+		return data, fmt.Errorf("repair failed: %w", err)
+	}
+	repaired, err := extractResponseContent(resp)
+	if err != nil {
+		return data, fmt.Errorf("repair failed: %w", err)
+	}
+
+	if err := validateResponse(schema, repaired); err != nil {
+		return data, err
+	}
+
+	l.Debug().Msg("ai response repaired")
+	return repaired, nil
+}
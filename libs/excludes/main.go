@@ -7,6 +7,8 @@ var Excludes = map[string]bool{
 	".svn":                        true,
 	".hg":                         true,
 	".DS_Store":                   true,
+	".ctxhistory":                 true,
+	".ctx":                        true,
 	"__MACOSX":                    true,
 	"__pycache__":                 true,
 	".tox":                        true,
@@ -0,0 +1,107 @@
+package ctxexcludes
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// OverrideFile is the path of the user's excludes override file, relative to the user config directory
+const OverrideFile = "ctx/excludes"
+
+const (
+	// SourceDefault marks an exclude from Excludes
+	SourceDefault = "default"
+	// SourceOverride marks an exclude added by the user's override file
+	SourceOverride = "override"
+)
+
+// Effective returns the excludes for the tree at root: the defaults, those of
+// the profiles detected in root, adjusted by the user's override file,
+// e.g. ~/.config/ctx/excludes. A missing override file is not an error.
+func Effective(root string) (map[string]bool, error) {
+	sources, err := Resolve(root)
+	if err != nil {
+		return nil, err
+	}
+
+	excludes := make(map[string]bool, len(sources))
+	for name := range sources {
+		excludes[name] = true
+	}
+	return excludes, nil
+}
+
+// Resolve returns the effective excludes for the tree at root, see Effective,
+// mapped to where each comes from: SourceDefault, a profile name or SourceOverride.
+func Resolve(root string) (map[string]string, error) {
+	sources := make(map[string]string, len(Excludes))
+	for name, ok := range Excludes {
+		if ok {
+			sources[name] = SourceDefault
+		}
+	}
+	for _, p := range Detect(root) {
+		for _, name := range p.Excludes {
+			if _, ok := sources[name]; !ok {
+				sources[name] = p.Name
+			}
+		}
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return sources, nil
+	}
+
+	f, err := os.Open(filepath.Join(dir, OverrideFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return sources, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	err = override(f, func(name string) { sources[name] = SourceOverride }, func(name string) { delete(sources, name) })
+	return sources, err
+}
+
+// Apply returns a copy of base adjusted by the override lines read from r.
+// Each line adds an exclude, unless prefixed with '-' in which case it removes
+// one. Blank lines and lines starting with '#' are ignored.
+func Apply(base map[string]bool, r io.Reader) (map[string]bool, error) {
+	excludes := clone(base)
+	err := override(r, func(name string) { excludes[name] = true }, func(name string) { delete(excludes, name) })
+	return excludes, err
+}
+
+// override calls add or remove for each override line read from r, see Apply
+func override(r io.Reader, add, remove func(string)) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if name, ok := strings.CutPrefix(line, "-"); ok {
+			remove(strings.TrimSpace(name))
+			continue
+		}
+		add(line)
+	}
+
+	return scanner.Err()
+}
+
+func clone(m map[string]bool) map[string]bool {
+	c := make(map[string]bool, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,63 @@
+package ctxexcludes
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Profile is a set of excludes for a project type, e.g. the build output of a framework.
+// It applies when one of its marker files exists at the root of the tree.
+type Profile struct {
+	Name     string
+	Markers  []string
+	Excludes []string
+}
+
+// Profiles are the per project type default excludes, added to Excludes when detected
+var Profiles = []Profile{
+	{
+		Name:     "node",
+		Markers:  []string{"package.json"},
+		Excludes: []string{".next", ".nuxt", ".svelte-kit", ".turbo", "build", "out", "__generated__", "*.generated.*"},
+	},
+	{
+		Name:     "python",
+		Markers:  []string{"pyproject.toml", "setup.py", "requirements.txt"},
+		Excludes: []string{".venv", "venv", "build", "*.egg-info"},
+	},
+	{
+		// migrations are generated by manage.py makemigrations
+		Name:     "django",
+		Markers:  []string{"manage.py"},
+		Excludes: []string{"migrations"},
+	},
+	{
+		Name:     "go",
+		Markers:  []string{"go.mod"},
+		Excludes: []string{"vendor"},
+	},
+	{
+		Name:     "java",
+		Markers:  []string{"pom.xml", "build.gradle", "build.gradle.kts"},
+		Excludes: []string{"build", "out"},
+	},
+	{
+		Name:     "rust",
+		Markers:  []string{"Cargo.toml"},
+		Excludes: []string{"target"},
+	},
+}
+
+// Detect returns the profiles whose marker files exist in root
+func Detect(root string) []Profile {
+	var detected []Profile
+	for _, p := range Profiles {
+		for _, m := range p.Markers {
+			if _, err := os.Stat(filepath.Join(root, m)); err == nil {
+				detected = append(detected, p)
+				break
+			}
+		}
+	}
+	return detected
+}
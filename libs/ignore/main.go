@@ -0,0 +1,373 @@
+package ctxignore
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	ctxexcludes "github.com/cyber-nic/ctx/libs/excludes"
+)
+
+const (
+	CtxIgnoreFile = ".ctxignore"
+	GitIgnoreFile = ".gitignore"
+
+	sourceDefault = "default"
+	sourceFlag    = "flag"
+)
+
+// Options controls which patterns make up the effective ignore set
+type Options struct {
+	// Excludes are the default excludes, ctxexcludes.Excludes when nil
+	Excludes map[string]bool
+	// Patterns are additional patterns, e.g. from command line flags. They are evaluated last.
+	Patterns []string
+	// NoDefaults, NoGitignore and NoCtxignore disable a source of patterns
+	NoDefaults  bool
+	NoGitignore bool
+	NoCtxignore bool
+}
+
+// rule is a single ignore pattern along with where it came from
+type rule struct {
+	// text is the pattern as written, e.g. "!/build/"
+	text    string
+	pattern string
+	// negate re-includes the paths the pattern matches, e.g. "!keep.log"
+	negate  bool
+	dirOnly bool
+	// anchored patterns match the path from base, others its base name at any depth
+	anchored bool
+	// base is the directory, relative to the root, the pattern is scoped to
+	base   string
+	source string
+	line   int
+}
+
+func (r rule) String() string {
+	if r.line > 0 {
+		return fmt.Sprintf("%s:%d:%s", r.source, r.line, r.text)
+	}
+	return fmt.Sprintf("%s:%s", r.source, r.text)
+}
+
+// IgnoreSet is the merged, ordered list of ignore rules for a tree
+type IgnoreSet struct {
+	rules []rule
+	// root and files locate the ignore files loaded by LoadDir
+	root  string
+	files []string
+	// loaded are the directories whose ignore files were loaded
+	loaded map[string]bool
+	// flagRules is the number of rules, at the end, from Options.Patterns. They
+	// stay last as directories are loaded.
+	flagRules int
+}
+
+// NewIgnoreSet merges the default excludes, the root .gitignore and .ctxignore
+// files, and the patterns provided in opts, in that order. The ignore files of
+// subdirectories are added by LoadDir as the tree is walked. Each source but
+// the patterns can be disabled through opts.
+func NewIgnoreSet(root string, opts Options) (*IgnoreSet, error) {
+	set := &IgnoreSet{root: root, loaded: map[string]bool{}}
+
+	// compiled-in defaults
+	excludes := opts.Excludes
+	if excludes == nil {
+		excludes = ctxexcludes.Excludes
+	}
+	if opts.NoDefaults {
+		excludes = nil
+	}
+	defaults := make([]string, 0, len(excludes))
+	for p, ok := range excludes {
+		if ok {
+			defaults = append(defaults, p)
+		}
+	}
+	sort.Strings(defaults)
+	for _, p := range defaults {
+		set.add(p, "", sourceDefault, 0)
+	}
+
+	if !opts.NoGitignore {
+		set.files = append(set.files, GitIgnoreFile)
+	}
+	if !opts.NoCtxignore {
+		set.files = append(set.files, CtxIgnoreFile)
+	}
+
+	n := len(set.rules)
+	for _, p := range opts.Patterns {
+		set.add(p, "", sourceFlag, 0)
+	}
+	set.flagRules = len(set.rules) - n
+
+	if err := set.LoadDir(""); err != nil {
+		return nil, fmt.Errorf("failed to load ignore files: %w", err)
+	}
+	return set, nil
+}
+
+// LoadDir adds the ignore files of the directory, relative to the root and
+// slash separated, scoped to the paths below it. A walk calls it on entering
+// each directory that isn't ignored, the ignore files of ignored directories
+// are never read. Loading a directory twice is a no-op.
+func (s *IgnoreSet) LoadDir(dir string) error {
+	if s.loaded[dir] {
+		return nil
+	}
+	s.loaded[dir] = true
+
+	loaded := IgnoreSet{}
+	for _, name := range s.files {
+		if err := loaded.load(filepath.Join(s.root, filepath.FromSlash(dir), name), dir); err != nil {
+			return err
+		}
+	}
+	s.rules = slices.Insert(s.rules, len(s.rules)-s.flagRules, loaded.rules...)
+	return nil
+}
+
+// EffectiveIgnore returns the ignore set of root, see NewIgnoreSet, with the
+// ignore files of every directory that isn't ignored, for callers that match
+// paths without walking the tree themselves. Nested ignore files only apply to
+// paths below their directory.
+func EffectiveIgnore(root string, opts Options) (IgnoreSet, error) {
+	set, err := NewIgnoreSet(root, opts)
+	if err != nil {
+		return IgnoreSet{}, err
+	}
+
+	// ignore files, from the root down. Directories already ignored are not visited.
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+		if ignored, _ := set.Matches(rel, true); ignored {
+			return filepath.SkipDir
+		}
+		return set.LoadDir(rel)
+	})
+	if err != nil {
+		return *set, fmt.Errorf("failed to load ignore files: %w", err)
+	}
+
+	return *set, nil
+}
+
+// load appends the patterns of an ignore file scoped to base. A missing file is not an error.
+func (s *IgnoreSet) load(file, base string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	source := path.Join(base, filepath.Base(file))
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		s.add(scanner.Text(), base, source, n)
+	}
+
+	return scanner.Err()
+}
+
+// add appends a pattern, skipping blank lines and comments. A leading \ escapes
+// a pattern starting with # or !.
+func (s *IgnoreSet) add(pattern, base, source string, line int) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return
+	}
+
+	r := rule{text: pattern, base: base, source: source, line: line}
+	if strings.HasPrefix(pattern, "!") {
+		r.negate = true
+		pattern = pattern[1:]
+	} else if strings.HasPrefix(pattern, `\#`) || strings.HasPrefix(pattern, `\!`) {
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") {
+		r.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	// a slash but the trailing one anchors the pattern, e.g. /dist or build/out
+	r.anchored = strings.Contains(pattern, "/")
+	r.pattern = strings.TrimPrefix(pattern, "/")
+	if r.pattern == "" {
+		return
+	}
+
+	s.rules = append(s.rules, r)
+}
+
+// Matches reports whether the path, relative to the root and slash separated,
+// is ignored, either itself or through one of its parent directories. The last
+// rule matching a path decides, a negated one re-including it. reason identifies
+// that rule, e.g. ".ctxignore:3:*.log", and is empty when no rule matches.
+//
+// Unlike git, an anchored negation re-includes a path below an ignored
+// directory, e.g. "generated/" then "!generated/schema.sql". That directory is
+// then not ignored itself, so that the walk descends into it, but its other
+// entries are.
+func (s IgnoreSet) Matches(p string, isDir bool) (bool, string) {
+	p = strings.TrimPrefix(filepath.ToSlash(p), "./")
+
+	parts := strings.Split(p, "/")
+	cur := -1
+	for i := 1; i <= len(parts); i++ {
+		sub := strings.Join(parts[:i], "/")
+		dir := i < len(parts) || isDir
+
+		// below an ignored directory, only an anchored negation can re-include a path
+		ignoredAbove := cur >= 0 && !s.rules[cur].negate
+		after := -1
+		if ignoredAbove {
+			after = cur
+		}
+		if idx := s.last(sub, dir, after, ignoredAbove); idx >= 0 {
+			cur = idx
+		} else if !ignoredAbove {
+			cur = -1
+		}
+
+		if cur < 0 || s.rules[cur].negate {
+			continue
+		}
+		if dir {
+			if idx := s.reincluded(sub, cur); idx >= 0 {
+				if i == len(parts) {
+					return false, s.rules[idx].String()
+				}
+				continue
+			}
+		}
+		return true, s.rules[cur].String()
+	}
+
+	if cur < 0 {
+		return false, ""
+	}
+	return false, s.rules[cur].String()
+}
+
+// last returns the index of the last rule after the given one matching the path
+// itself, -1 when none does. Below an ignored directory, unanchored negations are skipped.
+func (s IgnoreSet) last(p string, isDir bool, after int, ignoredAbove bool) int {
+	for i := len(s.rules) - 1; i > after; i-- {
+		r := s.rules[i]
+		if r.dirOnly && !isDir {
+			continue
+		}
+		if ignoredAbove && r.negate && !r.anchored {
+			continue
+		}
+		if rel, ok := r.relative(p); ok && r.matches(rel) {
+			return i
+		}
+	}
+	return -1
+}
+
+// reincluded returns the index of an anchored negation after the given rule
+// that may match a path below the directory, -1 when there is none
+func (s IgnoreSet) reincluded(dir string, after int) int {
+	for i := after + 1; i < len(s.rules); i++ {
+		r := s.rules[i]
+		if !r.negate || !r.anchored {
+			continue
+		}
+		if rel, ok := r.relative(dir); ok && matchesBelow(strings.Split(r.pattern, "/"), strings.Split(rel, "/")) {
+			return i
+		}
+	}
+	return -1
+}
+
+// relative returns the path relative to the rule base, false when the path is not below it
+func (r rule) relative(p string) (string, bool) {
+	if r.base == "" {
+		return p, true
+	}
+	return strings.CutPrefix(p, r.base+"/")
+}
+
+// matches reports whether the pattern matches the path relative to the rule base
+func (r rule) matches(rel string) bool {
+	if !r.anchored {
+		matched, _ := path.Match(r.pattern, path.Base(rel))
+		return matched
+	}
+	return matchSegments(strings.Split(r.pattern, "/"), strings.Split(rel, "/"))
+}
+
+// matchesBelow reports whether the pattern may match a path below the directory
+func matchesBelow(pattern, dir []string) bool {
+	for len(dir) > 0 {
+		if len(pattern) == 0 {
+			return false
+		}
+		if pattern[0] == "**" {
+			return true
+		}
+		if matched, _ := path.Match(pattern[0], dir[0]); !matched {
+			return false
+		}
+		pattern, dir = pattern[1:], dir[1:]
+	}
+	return len(pattern) > 0
+}
+
+// matchSegments matches a path against a pattern, segment by segment. A **
+// segment matches any number of segments, at least one when it ends the
+// pattern: build/** matches the contents of build, not build itself.
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			if len(rest) == 0 {
+				return len(parts) > 0
+			}
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(rest, parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(parts) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], parts[0]); !matched {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+
+	return len(parts) == 0
+}
@@ -0,0 +1,421 @@
+package ctxignore
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testExcludes are the default excludes of the tests, unless a case sets its own
+var testExcludes = map[string]bool{"node_modules": true, ".git": true}
+
+// writeTree creates the files, by slash separated path, below a temp root
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for p, content := range files {
+		p = filepath.Join(root, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// walkMatches matches the path like a walk of the tree would: the ignore files
+// of its parent directories are loaded from the root down, until one is ignored
+func walkMatches(t *testing.T, set *IgnoreSet, p string, isDir bool) (bool, string) {
+	t.Helper()
+	dirs := strings.Split(p, "/")
+	for i := 1; i < len(dirs); i++ {
+		dir := path.Join(dirs[:i]...)
+		if ignored, _ := set.Matches(dir, true); ignored {
+			break
+		}
+		if err := set.LoadDir(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return set.Matches(p, isDir)
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		opts  Options
+		path  string
+		isDir bool
+		// ignored and rule are the expected result of Matches, rule being the deciding rule
+		ignored bool
+		rule    string
+	}{
+		{
+			name: "no rule",
+			path: "main.go",
+		},
+		{
+			name:    "default exclude",
+			path:    "node_modules",
+			isDir:   true,
+			ignored: true,
+			rule:    "default:node_modules",
+		},
+		{
+			name:    "below a default exclude",
+			path:    "web/node_modules/react/index.js",
+			ignored: true,
+			rule:    "default:node_modules",
+		},
+		{
+			name:    "root ignore file",
+			files:   map[string]string{".ctxignore": "# logs\n*.log\n"},
+			path:    "logs/app.log",
+			ignored: true,
+			rule:    ".ctxignore:2:*.log",
+		},
+		{
+			name:    "nested ignore file",
+			files:   map[string]string{"sub/.ctxignore": "*.gen\n"},
+			path:    "sub/deep/a.gen",
+			ignored: true,
+			rule:    "sub/.ctxignore:1:*.gen",
+		},
+		{
+			name:  "nested ignore file outside its directory",
+			files: map[string]string{"sub/.ctxignore": "*.gen\n"},
+			path:  "other/a.gen",
+		},
+		{
+			name:    "nested anchored rule",
+			files:   map[string]string{"sub/.ctxignore": "/out\n"},
+			path:    "sub/out",
+			isDir:   true,
+			ignored: true,
+			rule:    "sub/.ctxignore:1:/out",
+		},
+		{
+			name:  "nested anchored rule below its directory",
+			files: map[string]string{"sub/.ctxignore": "/out\n"},
+			path:  "sub/pkg/out",
+			isDir: true,
+		},
+		{
+			name:    "anchored rule",
+			files:   map[string]string{".ctxignore": "/build\n"},
+			path:    "build/main.o",
+			ignored: true,
+			rule:    ".ctxignore:1:/build",
+		},
+		{
+			name:  "anchored rule below the root",
+			files: map[string]string{".ctxignore": "/build\n"},
+			path:  "src/build/main.o",
+		},
+		{
+			name:  "negation",
+			files: map[string]string{".ctxignore": "*.log\n!keep.log\n"},
+			path:  "keep.log",
+			rule:  ".ctxignore:2:!keep.log",
+		},
+		{
+			name:  "nested negation",
+			files: map[string]string{".ctxignore": "*.log\n", "sub/.ctxignore": "!debug.log\n"},
+			path:  "sub/debug.log",
+			rule:  "sub/.ctxignore:1:!debug.log",
+		},
+		{
+			name:    "nested negation outside its directory",
+			files:   map[string]string{".ctxignore": "*.log\n", "sub/.ctxignore": "!debug.log\n"},
+			path:    "debug.log",
+			ignored: true,
+			rule:    ".ctxignore:1:*.log",
+		},
+		{
+			name:  "patterns after nested ignore files",
+			files: map[string]string{"sub/.ctxignore": "*.md\n"},
+			opts:  Options{Patterns: []string{"!README.md"}},
+			path:  "sub/README.md",
+			rule:  "flag:!README.md",
+		},
+		// the sources in order: defaults, .gitignore then .ctxignore from the root down, patterns
+		{
+			name:  ".gitignore over the defaults",
+			files: map[string]string{".gitignore": "!node_modules\n"},
+			path:  "node_modules",
+			isDir: true,
+			rule:  ".gitignore:1:!node_modules",
+		},
+		{
+			name:  ".ctxignore over .gitignore",
+			files: map[string]string{".gitignore": "*.log\n", ".ctxignore": "!app.log\n"},
+			path:  "app.log",
+			rule:  ".ctxignore:1:!app.log",
+		},
+		{
+			name:    "nested .gitignore",
+			files:   map[string]string{"sub/.gitignore": "*.out\n"},
+			path:    "sub/a.out",
+			ignored: true,
+			rule:    "sub/.gitignore:1:*.out",
+		},
+		{
+			name:  "nested .gitignore over the root .ctxignore",
+			files: map[string]string{".ctxignore": "*.snap\n", "sub/.gitignore": "!*.snap\n"},
+			path:  "sub/a.snap",
+			rule:  "sub/.gitignore:1:!*.snap",
+		},
+		{
+			name:    "patterns over .ctxignore",
+			files:   map[string]string{".ctxignore": "!*.tmp\n"},
+			opts:    Options{Patterns: []string{"*.tmp"}},
+			path:    "a.tmp",
+			ignored: true,
+			rule:    "flag:*.tmp",
+		},
+		{
+			name: "without defaults",
+			opts: Options{NoDefaults: true},
+			path: "node_modules/react/index.js",
+		},
+		{
+			name:  "without .gitignore",
+			files: map[string]string{".gitignore": "*.log\n", "sub/.gitignore": "*.log\n"},
+			opts:  Options{NoGitignore: true},
+			path:  "sub/app.log",
+		},
+		{
+			name:    "without .ctxignore",
+			files:   map[string]string{".gitignore": "*.log\n", ".ctxignore": "!app.log\n"},
+			opts:    Options{NoCtxignore: true},
+			path:    "app.log",
+			ignored: true,
+			rule:    ".gitignore:1:*.log",
+		},
+		// gitignore glob semantics
+		{
+			name:    "contents of a directory",
+			files:   map[string]string{".ctxignore": "build/**\n"},
+			path:    "build/out/main.o",
+			ignored: true,
+			rule:    ".ctxignore:1:build/**",
+		},
+		{
+			name:  "contents of a directory, not the directory",
+			files: map[string]string{".ctxignore": "build/**\n"},
+			path:  "build",
+			isDir: true,
+		},
+		{
+			name:    "any depth",
+			files:   map[string]string{".ctxignore": "**/*.tmp\n"},
+			path:    "a/b/c/x.tmp",
+			ignored: true,
+			rule:    ".ctxignore:1:**/*.tmp",
+		},
+		{
+			name:    "any depth at the root",
+			files:   map[string]string{".ctxignore": "**/*.tmp\n"},
+			path:    "x.tmp",
+			ignored: true,
+			rule:    ".ctxignore:1:**/*.tmp",
+		},
+		{
+			name:    "inner double star",
+			files:   map[string]string{".ctxignore": "docs/**/draft.md\n"},
+			path:    "docs/a/b/draft.md",
+			ignored: true,
+			rule:    ".ctxignore:1:docs/**/draft.md",
+		},
+		{
+			name:    "leading slash",
+			files:   map[string]string{".ctxignore": "/dist\n"},
+			path:    "dist",
+			isDir:   true,
+			ignored: true,
+			rule:    ".ctxignore:1:/dist",
+		},
+		{
+			name:  "leading slash below the root",
+			files: map[string]string{".ctxignore": "/dist\n"},
+			path:  "web/dist",
+			isDir: true,
+		},
+		{
+			name:  "inner slash anchors",
+			files: map[string]string{".ctxignore": "web/dist\n"},
+			path:  "app/web/dist",
+			isDir: true,
+		},
+		{
+			name:    "directory only",
+			files:   map[string]string{".ctxignore": "cache/\n"},
+			path:    "a/cache/x",
+			ignored: true,
+			rule:    ".ctxignore:1:cache/",
+		},
+		{
+			name:  "directory only, a file",
+			files: map[string]string{".ctxignore": "cache/\n"},
+			path:  "a/cache",
+		},
+		{
+			name:  "no prefix match",
+			files: map[string]string{".ctxignore": "foo\n"},
+			path:  "foobar",
+		},
+		{
+			name:  "no prefix match of a directory",
+			files: map[string]string{".ctxignore": "foo\n"},
+			path:  "foobar/main.go",
+		},
+		{
+			name:  "negated glob",
+			files: map[string]string{".ctxignore": "**/*.tmp\n!a/**/*.tmp\n"},
+			path:  "a/b/x.tmp",
+			rule:  ".ctxignore:2:!a/**/*.tmp",
+		},
+		{
+			name:    "escaped hash",
+			files:   map[string]string{".ctxignore": "\\#notes\n"},
+			path:    "#notes",
+			ignored: true,
+			rule:    ".ctxignore:1:\\#notes",
+		},
+		// interleaved patterns, the last matching one decides
+		{
+			name:    "excluded",
+			files:   map[string]string{".ctxignore": "*.txt\n!keep-*.txt\nkeep-not.txt\n"},
+			path:    "b.txt",
+			ignored: true,
+			rule:    ".ctxignore:1:*.txt",
+		},
+		{
+			name:  "included again",
+			files: map[string]string{".ctxignore": "*.txt\n!keep-*.txt\nkeep-not.txt\n"},
+			path:  "docs/keep-a.txt",
+			rule:  ".ctxignore:2:!keep-*.txt",
+		},
+		{
+			name:    "excluded again",
+			files:   map[string]string{".ctxignore": "*.txt\n!keep-*.txt\nkeep-not.txt\n"},
+			path:    "keep-not.txt",
+			ignored: true,
+			rule:    ".ctxignore:3:keep-not.txt",
+		},
+		{
+			name:  "directory included again",
+			files: map[string]string{".ctxignore": "logs/\n!logs/\n"},
+			path:  "logs/app.log",
+		},
+		// an anchored negation re-includes a path below an ignored directory, unlike git
+		{
+			name:  "re-included below an ignored directory",
+			files: map[string]string{".ctxignore": "generated/\n!generated/schema.sql\n"},
+			path:  "generated/schema.sql",
+			rule:  ".ctxignore:2:!generated/schema.sql",
+		},
+		{
+			name:  "directory of a re-included path",
+			files: map[string]string{".ctxignore": "generated/\n!generated/schema.sql\n"},
+			path:  "generated",
+			isDir: true,
+			rule:  ".ctxignore:2:!generated/schema.sql",
+		},
+		{
+			name:    "other entries of that directory",
+			files:   map[string]string{".ctxignore": "generated/\n!generated/schema.sql\n"},
+			path:    "generated/models.go",
+			ignored: true,
+			rule:    ".ctxignore:1:generated/",
+		},
+		{
+			name:  "re-included deeper",
+			files: map[string]string{".ctxignore": "generated/\n!generated/sql/schema.sql\n"},
+			path:  "generated/sql/schema.sql",
+			rule:  ".ctxignore:2:!generated/sql/schema.sql",
+		},
+		{
+			name:    "siblings of a path re-included deeper",
+			files:   map[string]string{".ctxignore": "generated/\n!generated/sql/schema.sql\n"},
+			path:    "generated/sql/seed.sql",
+			ignored: true,
+			rule:    ".ctxignore:1:generated/",
+		},
+		{
+			name:    "unanchored negation below an ignored directory",
+			files:   map[string]string{".ctxignore": "generated/\n!schema.sql\n"},
+			path:    "generated/schema.sql",
+			ignored: true,
+			rule:    ".ctxignore:1:generated/",
+		},
+		{
+			name:    "excluded after the re-inclusion",
+			files:   map[string]string{".ctxignore": "generated/\n!generated/schema.sql\n*.sql\n"},
+			path:    "generated/schema.sql",
+			ignored: true,
+			rule:    ".ctxignore:3:*.sql",
+		},
+		// like git, the ignore files of an ignored directory are not read
+		{
+			name:    "ignore file of an ignored directory",
+			files:   map[string]string{".ctxignore": "vendor/\n", "vendor/.ctxignore": "!*\n"},
+			path:    "vendor/lib.go",
+			ignored: true,
+			rule:    ".ctxignore:1:vendor/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := writeTree(t, tt.files)
+			opts := tt.opts
+			if opts.Excludes == nil {
+				opts.Excludes = testExcludes
+			}
+
+			set, err := EffectiveIgnore(root, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ignored, rule := set.Matches(tt.path, tt.isDir); ignored != tt.ignored || rule != tt.rule {
+				t.Errorf("EffectiveIgnore: Matches(%q) = %t, %q, want %t, %q", tt.path, ignored, rule, tt.ignored, tt.rule)
+			}
+
+			walked, err := NewIgnoreSet(root, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ignored, rule := walkMatches(t, walked, tt.path, tt.isDir); ignored != tt.ignored || rule != tt.rule {
+				t.Errorf("walk: Matches(%q) = %t, %q, want %t, %q", tt.path, ignored, rule, tt.ignored, tt.rule)
+			}
+		})
+	}
+}
+
+func TestLoadDirOnce(t *testing.T) {
+	root := writeTree(t, map[string]string{"sub/.ctxignore": "*.gen\n"})
+	set, err := NewIgnoreSet(root, Options{Excludes: testExcludes, Patterns: []string{"*.tmp"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		if err := set.LoadDir("sub"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	texts := []string{}
+	for _, r := range set.rules {
+		texts = append(texts, r.String())
+	}
+	want := []string{"default:.git", "default:node_modules", "sub/.ctxignore:1:*.gen", "flag:*.tmp"}
+	if strings.Join(texts, " ") != strings.Join(want, " ") {
+		t.Errorf("rules are %q, want %q", texts, want)
+	}
+}
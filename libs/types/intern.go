@@ -0,0 +1,164 @@
+package ctxtypes
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// FeatureHeader lists the optional protocol features a client asks for when
+	// connecting. The server echoes the ones it supports in its upgrade response.
+	FeatureHeader = "X-Ctx-Features"
+	// FeatureInternedKeywords sends keywords as indexes into a shared table, see EncodeKeywords
+	FeatureInternedKeywords = "interned-keywords"
+	// FeatureChunkedLoad sends large contexts in acknowledged chunks, see ContextChunk
+	FeatureChunkedLoad = "chunked-load"
+	// FeatureStreamedLoad sends the file system tree in batches of nodes as it is walked, see CtxRequest.Nodes
+	FeatureStreamedLoad = "streamed-load"
+	// FeatureKeepalive pings the connection both ways, every PingIntervalHeader, and
+	// times out reads that see neither a message nor a ping for two intervals
+	FeatureKeepalive = "keepalive"
+	// PingIntervalHeader is the ping interval of a keepalive connection, a Go duration
+	// the server sets in its upgrade response, e.g. "30s"
+	PingIntervalHeader = "X-Ctx-Ping-Interval"
+)
+
+// EncodeKeywords returns a copy of the context where the keywords of the tree
+// and of the flat file list are replaced by indexes into KeywordTable. The most
+// frequent keywords get the lowest, shortest, indexes.
+func EncodeKeywords(ctx ApplicationContext) ApplicationContext {
+	counts := map[string]int{}
+	WalkFiles(ctx, func(_ string, node *FileSystemNode) {
+		for _, k := range node.Keywords {
+			counts[k]++
+		}
+	})
+
+	table := make([]string, 0, len(counts))
+	for k := range counts {
+		table = append(table, k)
+	}
+	sort.Slice(table, func(i, j int) bool {
+		if counts[table[i]] != counts[table[j]] {
+			return counts[table[i]] > counts[table[j]]
+		}
+		return table[i] < table[j]
+	})
+
+	index := make(map[string]int, len(table))
+	for i, k := range table {
+		index[k] = i
+	}
+	encode := func(keywords []string) []int {
+		if keywords == nil {
+			return nil
+		}
+		ids := make([]int, len(keywords))
+		for i, k := range keywords {
+			ids[i] = index[k]
+		}
+		return ids
+	}
+
+	var copyNode func(node FileSystemNode) FileSystemNode
+	copyNode = func(node FileSystemNode) FileSystemNode {
+		if len(node.Keywords) > 0 {
+			node.KeywordIDs = encode(node.Keywords)
+			node.Keywords = nil
+		}
+		if node.Children != nil {
+			children := make(map[string]*FileSystemNode, len(node.Children))
+			for name, child := range node.Children {
+				if child == nil {
+					continue
+				}
+				c := copyNode(*child)
+				children[name] = &c
+			}
+			node.Children = children
+		}
+		return node
+	}
+
+	encoded := ctx
+	encoded.KeywordTable = table
+	if ctx.FileSystem != nil {
+		encoded.FileSystem = make(map[string]FileSystemNode, len(ctx.FileSystem))
+		for name, root := range ctx.FileSystem {
+			encoded.FileSystem[name] = copyNode(root)
+		}
+	}
+	if ctx.Files != nil {
+		encoded.Files = nil
+		encoded.FileKeywordIDs = make(map[string][]int, len(ctx.Files))
+		for p, keywords := range ctx.Files {
+			encoded.FileKeywordIDs[p] = encode(keywords)
+		}
+	}
+
+	return encoded
+}
+
+// DecodeKeywords reverses EncodeKeywords. A context that is not encoded is returned as is.
+func DecodeKeywords(ctx ApplicationContext) (ApplicationContext, error) {
+	if len(ctx.KeywordTable) == 0 && ctx.FileKeywordIDs == nil {
+		return ctx, nil
+	}
+
+	decode := func(ids []int) ([]string, error) {
+		if ids == nil {
+			return nil, nil
+		}
+		keywords := make([]string, len(ids))
+		for i, id := range ids {
+			if id < 0 || id >= len(ctx.KeywordTable) {
+				return nil, fmt.Errorf("keyword index %d out of range", id)
+			}
+			keywords[i] = ctx.KeywordTable[id]
+		}
+		return keywords, nil
+	}
+
+	var decodeNode func(node *FileSystemNode) error
+	decodeNode = func(node *FileSystemNode) error {
+		if len(node.KeywordIDs) > 0 {
+			keywords, err := decode(node.KeywordIDs)
+			if err != nil {
+				return err
+			}
+			node.Keywords = keywords
+			node.KeywordIDs = nil
+		}
+		for _, child := range node.Children {
+			if child == nil {
+				continue
+			}
+			if err := decodeNode(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for name, root := range ctx.FileSystem {
+		if err := decodeNode(&root); err != nil {
+			return ctx, err
+		}
+		ctx.FileSystem[name] = root
+	}
+
+	if ctx.FileKeywordIDs != nil {
+		ctx.Files = make(map[string][]string, len(ctx.FileKeywordIDs))
+		for p, ids := range ctx.FileKeywordIDs {
+			keywords, err := decode(ids)
+			if err != nil {
+				return ctx, err
+			}
+			ctx.Files[p] = keywords
+		}
+		ctx.FileKeywordIDs = nil
+	}
+
+	ctx.KeywordTable = nil
+	return ctx, nil
+}
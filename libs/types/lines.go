@@ -0,0 +1,62 @@
+package ctxtypes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LineFormat is how the lines of a work target are numbered for the model
+type LineFormat string
+
+const (
+	// LineFormatPipe prefixes each line with its number and ` | `, e.g. `12 | `
+	LineFormatPipe LineFormat = ""
+	// LineFormatBracket prefixes each line with its number between ⟦ ⟧, unlikely to appear in code, e.g. `⟦12⟧ `
+	LineFormatBracket LineFormat = "bracket"
+	// LineFormatNone sends the content as is
+	LineFormatNone LineFormat = "none"
+)
+
+var lineFormatPatterns = map[LineFormat]*regexp.Regexp{
+	LineFormatPipe:    regexp.MustCompile(`^\d+ \| `),
+	LineFormatBracket: regexp.MustCompile(`^⟦\d+⟧ `),
+}
+
+// ParseLineFormat parses a -line-format value: pipe, bracket or none
+func ParseLineFormat(s string) (LineFormat, error) {
+	switch strings.TrimSpace(s) {
+	case "", "pipe":
+		return LineFormatPipe, nil
+	case string(LineFormatBracket):
+		return LineFormatBracket, nil
+	case string(LineFormatNone):
+		return LineFormatNone, nil
+	}
+	return LineFormatPipe, fmt.Errorf("unknown line format %q, expected pipe, bracket or none", s)
+}
+
+// Prefix returns the decoration of the 1-based line n, empty when lines aren't numbered
+func (f LineFormat) Prefix(n int) string {
+	switch f {
+	case LineFormatBracket:
+		return fmt.Sprintf("⟦%d⟧ ", n)
+	case LineFormatNone:
+		return ""
+	default:
+		return fmt.Sprintf("%d | ", n)
+	}
+}
+
+// Strip removes the decoration from the start of a line. ok is false when the line isn't decorated.
+func (f LineFormat) Strip(line string) (string, bool) {
+	re, numbered := lineFormatPatterns[f]
+	if !numbered {
+		return line, false
+	}
+	loc := re.FindStringIndex(line)
+	if loc == nil {
+		return line, false
+	}
+	return line[loc[1]:], true
+}
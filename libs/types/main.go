@@ -1,25 +1,77 @@
 package ctxtypes
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Todo is a TODO, FIXME, HACK or XXX comment and its 1-based line
+type Todo struct {
+	Line int    `json:"line"`
+	Kind string `json:"kind"`
+	Text string `json:"text"`
+}
+
 // FileSystemNode represents a node in a file system tree
 type FileSystemNode struct {
-	Directory bool                       `json:"dir,omitempty"`
-	Children  map[string]*FileSystemNode `json:"children,omitempty"`
-	Skip      bool                       `json:"skip,omitempty"`
-	Keywords  []string                   `json:"keywords,omitempty"`
+	Directory  bool                       `json:"dir,omitempty"`
+	Children   map[string]*FileSystemNode `json:"children,omitempty"`
+	Skip       bool                       `json:"skip,omitempty"`
+	SkipReason string                     `json:"skip_reason,omitempty"`
+	Keywords   []string                   `json:"keywords,omitempty"`
+	// KeywordIDs replaces Keywords in an encoded context, see EncodeKeywords
+	KeywordIDs []int `json:"kw,omitempty"`
+	// Role is what a file is for, see ClassifyRole
+	Role FileRole `json:"role,omitempty"`
+	// Todos are the TODO, FIXME, HACK and XXX comments of a file
+	Todos []Todo `json:"todos,omitempty"`
+	// Summary marks a directory whose children are replaced by their aggregated keywords
+	Summary   bool `json:"summary,omitempty"`
+	FileCount int  `json:"file_count,omitempty"`
 }
 
+// RootKey is the key of the root directory in ApplicationContext.FileSystem
+const RootKey = "."
+
 type ApplicationContext struct {
+	// Root is the absolute path of the directory the context was built from. All other paths are relative to it.
+	Root              string                    `json:"root,omitempty"`
 	FileSystem        map[string]FileSystemNode `json:"fs,omitempty"`
 	FileSystemDetails []string                  `json:"fs_details,omitempty"`
-	FileContents      map[string]string         `json:"file_contents,omitempty"`
+	// Files is the flat alternative to FileSystem, mapping file paths to keywords
+	Files map[string][]string `json:"files,omitempty"`
+	// KeywordTable and FileKeywordIDs are set in an encoded context, see EncodeKeywords
+	KeywordTable   []string          `json:"keyword_table,omitempty"`
+	FileKeywordIDs map[string][]int  `json:"files_kw,omitempty"`
+	FileContents   map[string]string `json:"file_contents,omitempty"`
+	// FileKeywords maps files whose content is withheld, e.g. large additional context files, to their keywords
+	FileKeywords map[string][]string `json:"file_keywords,omitempty"`
+	Pinned       []string            `json:"pinned,omitempty"`
+	References   []string            `json:"references,omitempty"`
+	// FileHashes references file contents previously uploaded to the server, by path
+	FileHashes map[string]string `json:"file_hashes,omitempty"`
+}
+
+// ContentHash returns the hash identifying a file's content in the server cache
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
 }
 
 type CtxStep string
 
 const (
 	CtxStepLoadContext   CtxStep = "load"
+	CtxStepPlan          CtxStep = "plan"
 	CtxStepFileSelection CtxStep = "select"
 	CtxStepCodeWork      CtxStep = "work"
+	CtxStepReview        CtxStep = "review"
+	CtxStepStatus        CtxStep = "status"
+	CtxStepManifest      CtxStep = "manifest"
+	CtxStepUpload        CtxStep = "upload"
+	CtxStepLoadChunk     CtxStep = "load-chunk"
+	CtxStepLoadNodes     CtxStep = "load-nodes"
+	CtxStepWorkChunk     CtxStep = "work-chunk"
 )
 
 // CtxRequest represents a message sent from client to server
@@ -28,37 +80,261 @@ type CtxRequest struct {
 	Context    ApplicationContext `json:"context,omitempty"`
 	Step       CtxStep            `json:"step"`
 	UserPrompt string             `json:"userPrompt,omitempty"`
-	WorkPrompt string             `json:"workPrompt,omitempty"`
+	WorkTarget *WorkTarget        `json:"workTarget,omitempty"`
+	Hints      []string           `json:"hints,omitempty"`
+	WithTests  bool               `json:"withTests,omitempty"`
+	// Manifest maps file paths to content hashes (manifest step)
+	Manifest map[string]string `json:"manifest,omitempty"`
+	// Blobs maps content hashes to file contents (upload step)
+	Blobs      map[string]string `json:"blobs,omitempty"`
+	Candidates int               `json:"candidates,omitempty"`
+	// WorkFormat selects the work step output, a patch when empty
+	WorkFormat WorkFormat `json:"workFormat,omitempty"`
+	// NoContents is set when the client never sends file contents, only the work target's
+	NoContents bool `json:"noContents,omitempty"`
+	// NoKeywords is set when the context holds the file structure only
+	NoKeywords bool `json:"noKeywords,omitempty"`
+	// Explain asks for the keywords and relevance behind each selected file (select step)
+	Explain bool `json:"explain,omitempty"`
+	// Plan is the implementation plan approved by the user, followed by the select and work steps
+	Plan *StepPlanData `json:"plan,omitempty"`
+	// Diff holds the changes under review (review step)
+	Diff string `json:"diff,omitempty"`
+	// LineFormat is how the lines of the work target are numbered (work step)
+	LineFormat LineFormat `json:"lineFormat,omitempty"`
+	// ExtraInstructions are one-off constraints from the user, appended to the step instructions
+	ExtraInstructions []string `json:"extraInstructions,omitempty"`
+	// ProjectTypes are the project types detected at the root of the tree, e.g. go or node (work step)
+	ProjectTypes []string `json:"projectTypes,omitempty"`
+	// Personas replace the server's guidance for a project type, an empty one disables it (work step)
+	Personas map[string]string `json:"personas,omitempty"`
+	// TraceParent is the W3C trace context of the client span the request belongs to
+	TraceParent string `json:"traceparent,omitempty"`
+	// Chunk is a piece of a large context sent over several requests (load-chunk step)
+	Chunk *ContextChunk `json:"chunk,omitempty"`
+	// Nodes maps slash separated paths, relative to the root, to the nodes of the
+	// file system tree walked so far, without their children (load-nodes step).
+	// The following load request gets the assembled tree as its file system.
+	Nodes map[string]FileSystemNode `json:"nodes,omitempty"`
+	// Stream asks for the work step output as it is generated, see StepWorkChunkResponseSchema
+	Stream bool `json:"stream,omitempty"`
+}
+
+// ContextChunk carries a slice of the JSON encoded context of a load request.
+// Chunks are sent in order, the context is loaded once the last one is received.
+type ContextChunk struct {
+	Index int    `json:"index"`
+	Total int    `json:"total"`
+	Data  string `json:"data"`
+}
+
+// WorkFormat is the shape of the changes returned by the work step
+type WorkFormat string
+
+const (
+	WorkFormatPatch WorkFormat = ""
+	WorkFormatEdits WorkFormat = "edits"
+)
+
+// WorkTarget is the file a work step operates on. Content is the raw file
+// content; the server is responsible for presenting it to the model.
+type WorkTarget struct {
+	Path      string        `json:"path"`
+	Operation FileOperation `json:"operation"`
+	Content   string        `json:"content,omitempty"`
+}
+
+// TokenBudget is a client's token usage against the server's per client budget.
+// Step responses carry it after each generation when the server enforces a budget.
+type TokenBudget struct {
+	Used  int `json:"used"`
+	Limit int `json:"limit"`
 }
 
+// Remaining returns the tokens left in the budget
+func (b TokenBudget) Remaining() int {
+	return max(b.Limit-b.Used, 0)
+}
+
+// Response statuses
+const (
+	StatusOK          = "ok"
+	StatusRateLimited = "rate_limited"
+)
+
 // CtxResponse represents a message sent from server to client
 type CtxResponse struct {
 	DisplayMessage string   `json:"display_message,omitempty"`
 	Instructions   []string `json:"instructions,omitempty"`
 }
 
+// StepStatusData describes the phase the server is in. Percent is optional.
+type StepStatusData struct {
+	Phase   string `json:"phase"`
+	Percent int    `json:"percent,omitempty"`
+}
+
+// StepStatusResponseSchema is sent by the server while a step is in progress.
+// It may precede any step response and carries no result.
+type StepStatusResponseSchema struct {
+	Timestamp string         `json:"timestamp"`
+	Step      string         `json:"step"`
+	Data      StepStatusData `json:"data"`
+}
+
+// StepWorkChunkResponseSchema carries a piece of the work step output as the
+// model generates it. Chunks precede the work step response, which completes
+// them and holds the validated result.
+type StepWorkChunkResponseSchema struct {
+	Timestamp string `json:"timestamp"`
+	Step      string `json:"step"`
+	Chunk     string `json:"chunk"`
+}
+
+// StepManifestResponseSchema lists the content hashes the server does not have
+type StepManifestResponseSchema struct {
+	Timestamp string   `json:"timestamp"`
+	Step      string   `json:"step"`
+	Status    string   `json:"status"`
+	Missing   []string `json:"missing"`
+}
+
+// StepUploadResponseSchema acknowledges uploaded contents
+type StepUploadResponseSchema struct {
+	Timestamp string `json:"timestamp"`
+	Step      string `json:"step"`
+	Status    string `json:"status"`
+	Stored    int    `json:"stored"`
+}
+
+// StepLoadChunkResponseSchema acknowledges a context chunk. Received is the
+// number of chunks received in order, i.e. the index of the next chunk expected.
+type StepLoadChunkResponseSchema struct {
+	Timestamp string `json:"timestamp"`
+	Step      string `json:"step"`
+	Status    string `json:"status"`
+	Received  int    `json:"received"`
+}
+
 type StepPreloadResponseSchema struct {
 	Step   string `json:"step"`
 	Status string `json:"status"`
 }
 
+// PlanStep is one step of an implementation plan and the files it affects
+type PlanStep struct {
+	Description string   `json:"description"`
+	Files       []string `json:"files"`
+}
+
+// StepPlanData is the plan step model output: an overview and the ordered steps of the change
+type StepPlanData struct {
+	Summary string     `json:"summary"`
+	Steps   []PlanStep `json:"steps"`
+}
+
+type StepPlanResponseSchema struct {
+	Timestamp string       `json:"timestamp"`
+	Step      string       `json:"step"`
+	Status    string       `json:"status"`
+	Data      StepPlanData `json:"data"`
+	Budget    *TokenBudget `json:"budget,omitempty"`
+}
+
+// ReviewSeverity ranks review comments
+type ReviewSeverity string
+
+const (
+	ReviewSeverityInfo    ReviewSeverity = "info"
+	ReviewSeverityWarning ReviewSeverity = "warning"
+	ReviewSeverityError   ReviewSeverity = "error"
+)
+
+// ReviewComment is a review remark on a line of a file, 1-based
+type ReviewComment struct {
+	Path     string         `json:"path"`
+	Line     int            `json:"line"`
+	Severity ReviewSeverity `json:"severity" jsonschema:"enum=info,enum=warning,enum=error"`
+	Message  string         `json:"message"`
+}
+
+// StepReviewData is the review step model output
+type StepReviewData struct {
+	Comments []ReviewComment `json:"comments"`
+}
+
+type StepReviewResponseSchema struct {
+	Timestamp string         `json:"timestamp"`
+	Step      string         `json:"step"`
+	Status    string         `json:"status"`
+	Data      StepReviewData `json:"data"`
+	Budget    *TokenBudget   `json:"budget,omitempty"`
+}
+
 type FileOperation int
 
 const (
 	FileOperationRemove FileOperation = -1
 	FileOperationUpdate FileOperation = 0
 	FileOperationCreate FileOperation = 1
+	FileOperationMove   FileOperation = 2
 )
 
+func (o FileOperation) String() string {
+	switch o {
+	case FileOperationRemove:
+		return "remove"
+	case FileOperationCreate:
+		return "create"
+	case FileOperationMove:
+		return "move"
+	default:
+		return "update"
+	}
+}
+
 type StepFileSelectItem struct {
-	Operation FileOperation
+	Operation FileOperation `jsonschema:"enum=-1,enum=0,enum=1,enum=2"`
 	Path      string
+	NewPath   string `json:"NewPath,omitempty"`
 	Reason    string
+	// MatchedKeywords and Relevance explain the selection in explain mode, see StepFileSelectExplainedItem
+	MatchedKeywords []string `json:"matched_keywords,omitempty" jsonschema:"-"`
+	Relevance       float64  `json:"relevance,omitempty" jsonschema:"-"`
+}
+
+// StepFileSelectExplainedItem is the schema of a selected file in explain mode,
+// with the keywords that drove its selection and its relevance, from 0 to 1
+type StepFileSelectExplainedItem struct {
+	Operation       FileOperation `jsonschema:"enum=-1,enum=0,enum=1,enum=2"`
+	Path            string
+	NewPath         string `json:"NewPath,omitempty"`
+	Reason          string
+	MatchedKeywords []string `json:"matched_keywords"`
+	Relevance       float64  `json:"relevance"`
+}
+
+// TargetPath returns the path the file will have once the operation is applied
+func (i StepFileSelectItem) TargetPath() string {
+	if i.Operation == FileOperationMove && i.NewPath != "" {
+		return i.NewPath
+	}
+	return i.Path
 }
 
 type StepFileSelectFiles struct {
 	Files      []StepFileSelectItem `json:"files"`
 	Additional []StepFileSelectItem `json:"additional_context_files"`
+	// Reason explains an empty selection
+	Reason string `json:"reason,omitempty"`
+}
+
+// StepFileSelectExplainedFiles is the select step model output in explain mode
+type StepFileSelectExplainedFiles struct {
+	Files      []StepFileSelectExplainedItem `json:"files"`
+	Additional []StepFileSelectExplainedItem `json:"additional_context_files"`
+	// Reason explains an empty selection
+	Reason string `json:"reason,omitempty"`
 }
 
 type StepFileSelectResponseSchema struct {
@@ -66,15 +342,57 @@ type StepFileSelectResponseSchema struct {
 	Step      string              `json:"step"`
 	Status    string              `json:"status"`
 	Data      StepFileSelectFiles `json:"data"`
+	Budget    *TokenBudget        `json:"budget,omitempty"`
 }
 
 type PatchData struct {
-	Patch string `json:"patch"`
+	Patch  string `json:"patch"`
+	Path   string `json:"path,omitempty"`
+	IsTest bool   `json:"is_test,omitempty"`
+	// Confidence is the model's own estimate, from 0 to 1, that the patch is correct and complete
+	Confidence *float64 `json:"confidence,omitempty" jsonschema:"minimum=0,maximum=1"`
+	// Assumptions are what the model assumed to write the patch, e.g. about code it didn't see
+	Assumptions []string `json:"assumptions,omitempty"`
+}
+
+// PatchDataWithTests is the work step model output when tests are requested
+type PatchDataWithTests struct {
+	Patch       string      `json:"patch"`
+	Confidence  *float64    `json:"confidence,omitempty" jsonschema:"minimum=0,maximum=1"`
+	Assumptions []string    `json:"assumptions,omitempty"`
+	Tests       []PatchData `json:"tests"`
+}
+
+// Position is a zero-based line and character offset, as in the language server protocol
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is the span of text between two positions, the end being exclusive
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// TextEdit replaces the text of a range with NewText
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
+// EditData is the work step model output when edits are requested
+type EditData struct {
+	Edits []TextEdit `json:"edits"`
 }
 
 type StepFileWorkResponseSchema struct {
-	Timestamp string    `json:"timestamp"`
-	Step      string    `json:"step"`
-	Status    string    `json:"status"`
-	Data      PatchData `json:"data"`
+	Timestamp  string       `json:"timestamp"`
+	Step       string       `json:"step"`
+	Status     string       `json:"status"`
+	Data       PatchData    `json:"data"`
+	Candidates []PatchData  `json:"candidates,omitempty"`
+	Tests      []PatchData  `json:"tests,omitempty"`
+	Edits      []TextEdit   `json:"edits,omitempty"`
+	Budget     *TokenBudget `json:"budget,omitempty"`
 }
@@ -0,0 +1,85 @@
+package ctxtypes
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// WalkFiles calls fn for every file node and summarized directory in the
+// context tree. The path passed to fn is relative to the root of the tree and
+// uses forward slashes.
+func WalkFiles(ctx ApplicationContext, fn func(p string, node *FileSystemNode)) {
+	var walk func(prefix string, node *FileSystemNode)
+
+	walk = func(prefix string, node *FileSystemNode) {
+		for name, child := range node.Children {
+			if child == nil {
+				continue
+			}
+			p := path.Join(prefix, name)
+			if child.Directory && !child.Summary {
+				walk(p, child)
+				continue
+			}
+			fn(p, child)
+		}
+	}
+
+	for _, root := range ctx.FileSystem {
+		walk("", &root)
+	}
+
+	for p, keywords := range ctx.Files {
+		fn(p, &FileSystemNode{Keywords: keywords})
+	}
+}
+
+// Flatten returns the files and summarized directories of the context tree mapped to their keywords
+func Flatten(ctx ApplicationContext) map[string][]string {
+	files := map[string][]string{}
+
+	WalkFiles(ctx, func(p string, node *FileSystemNode) {
+		// summarized directories keep a trailing slash to tell them apart from files
+		if node.Summary {
+			p += "/"
+		}
+		files[p] = node.Keywords
+	})
+
+	return files
+}
+
+// FindFilesByKeyword returns the sorted paths of files whose keywords contain keyword.
+func FindFilesByKeyword(ctx ApplicationContext, keyword string) []string {
+	return findFiles(ctx, func(k string) bool { return k == keyword })
+}
+
+// FindFilesByKeywordFold is the case-insensitive variant of FindFilesByKeyword.
+func FindFilesByKeywordFold(ctx ApplicationContext, keyword string) []string {
+	return findFiles(ctx, func(k string) bool { return strings.EqualFold(k, keyword) })
+}
+
+// FindFilesByKeywordPrefix returns the sorted paths of files having at least
+// one keyword that starts with prefix. The match is case-insensitive.
+func FindFilesByKeywordPrefix(ctx ApplicationContext, prefix string) []string {
+	prefix = strings.ToLower(prefix)
+	return findFiles(ctx, func(k string) bool { return strings.HasPrefix(strings.ToLower(k), prefix) })
+}
+
+func findFiles(ctx ApplicationContext, match func(keyword string) bool) []string {
+	paths := []string{}
+
+	WalkFiles(ctx, func(p string, node *FileSystemNode) {
+		for _, k := range node.Keywords {
+			if match(k) {
+				paths = append(paths, p)
+				return
+			}
+		}
+	})
+
+	sort.Strings(paths)
+
+	return paths
+}
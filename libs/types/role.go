@@ -0,0 +1,140 @@
+package ctxtypes
+
+import (
+	"path"
+	"strings"
+)
+
+// FileRole is what a file is for, used to bias the selection
+type FileRole string
+
+const (
+	RoleSource FileRole = "source"
+	RoleTest   FileRole = "test"
+	RoleConfig FileRole = "config"
+	RoleDocs   FileRole = "docs"
+	RoleBuild  FileRole = "build"
+	// RoleLockfile files pin dependency versions, their keywords are the pinned dependencies
+	RoleLockfile FileRole = "lockfile"
+)
+
+var (
+	// testDirs hold test code and fixtures, whatever their extension
+	testDirs = map[string]bool{"test": true, "tests": true, "__tests__": true, "spec": true, "testdata": true, "__mocks__": true}
+
+	docsDirs = map[string]bool{"doc": true, "docs": true}
+
+	buildNames = map[string]bool{
+		"makefile": true, "gnumakefile": true, "cmakelists.txt": true, "justfile": true, "taskfile.yml": true,
+		"build": true, "build.bazel": true, "workspace": true, "earthfile": true, "jenkinsfile": true,
+		"pom.xml": true, "build.gradle": true, "build.gradle.kts": true, "settings.gradle": true,
+		"setup.py": true, "build.rs": true, ".gitlab-ci.yml": true, ".goreleaser.yml": true, ".goreleaser.yaml": true,
+	}
+	buildExts = map[string]bool{".mk": true, ".bzl": true, ".dockerfile": true}
+
+	configNames = map[string]bool{
+		"go.mod": true, "go.work": true, "package.json": true, "pnpm-lock.yaml": true, "tsconfig.json": true,
+		"pyproject.toml": true, "setup.cfg": true, "pipfile": true, "pipfile.lock": true, "poetry.lock": true,
+		"cargo.toml": true, "gemfile": true, "gemfile.lock": true, "tox.ini": true, "procfile": true,
+	}
+	configExts = map[string]bool{
+		".json": true, ".yaml": true, ".yml": true, ".toml": true, ".ini": true, ".cfg": true, ".conf": true,
+		".env": true, ".properties": true, ".xml": true,
+	}
+
+	// lockNames are the lockfiles whose pinned dependencies are extracted
+	lockNames = map[string]bool{"go.sum": true, "package-lock.json": true, "yarn.lock": true, "cargo.lock": true}
+
+	docsNames = map[string]bool{"license": true, "copying": true, "authors": true, "changelog": true, "notice": true, "contributing": true}
+	docsExts  = map[string]bool{".md": true, ".mdx": true, ".rst": true, ".adoc": true, ".txt": true}
+
+	sourceExts = map[string]bool{
+		".go": true, ".py": true, ".js": true, ".jsx": true, ".mjs": true, ".cjs": true, ".ts": true, ".tsx": true,
+		".java": true, ".kt": true, ".scala": true, ".rs": true, ".c": true, ".h": true, ".cc": true, ".cpp": true,
+		".cxx": true, ".hpp": true, ".cs": true, ".rb": true, ".php": true, ".swift": true, ".sh": true, ".sql": true,
+		".vue": true, ".svelte": true, ".css": true, ".scss": true, ".html": true, ".proto": true,
+	}
+)
+
+// ClassifyRole returns the role of the file from its path and extension, empty
+// when none applies, e.g. for images. Tests win over the other roles, so that
+// fixtures under a test directory are tests, then lockfiles, build, config and
+// docs files, before any source code.
+func ClassifyRole(p string) FileRole {
+	p = path.Clean(strings.ReplaceAll(p, "\\", "/"))
+	base := strings.ToLower(path.Base(p))
+	ext := path.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	dirs := strings.Split(path.Dir(p), "/")
+
+	if isTestFile(path.Base(p), stem, ext) {
+		return RoleTest
+	}
+	for _, d := range dirs {
+		if testDirs[strings.ToLower(d)] {
+			return RoleTest
+		}
+	}
+
+	if lockNames[base] {
+		return RoleLockfile
+	}
+
+	switch {
+	case buildNames[base], buildExts[ext], strings.HasPrefix(base, "dockerfile"), strings.HasPrefix(base, "containerfile"):
+		return RoleBuild
+	case len(dirs) >= 2 && dirs[0] == ".github" && dirs[1] == "workflows":
+		return RoleBuild
+	}
+
+	switch {
+	case configNames[base], configExts[ext]:
+		return RoleConfig
+	// requirements.txt and friends aren't docs despite their extension
+	case strings.HasPrefix(base, "requirements") && ext == ".txt":
+		return RoleConfig
+	// tool settings written as code, e.g. vite.config.ts or .eslintrc.js
+	case strings.HasSuffix(stem, ".config"), strings.HasPrefix(base, ".") && strings.Contains(base, "rc"):
+		return RoleConfig
+	// dotfiles such as .gitignore or .editorconfig
+	case strings.HasPrefix(base, ".") && ext == base:
+		return RoleConfig
+	}
+
+	if docsNames[stem] || docsExts[ext] {
+		return RoleDocs
+	}
+	if sourceExts[ext] {
+		return RoleSource
+	}
+	for _, d := range dirs {
+		if docsDirs[strings.ToLower(d)] {
+			return RoleDocs
+		}
+	}
+
+	return ""
+}
+
+// isTestFile reports whether the name follows a language's test file
+// convention. stem and ext are lowercase, name keeps its case.
+func isTestFile(name, stem, ext string) bool {
+	switch ext {
+	case ".go":
+		return strings.HasSuffix(stem, "_test")
+	case ".py":
+		// conftest.py holds pytest fixtures and hooks, only ever loaded by tests
+		return strings.HasPrefix(stem, "test_") || strings.HasSuffix(stem, "_test") || stem == "conftest"
+	case ".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx":
+		return strings.HasSuffix(stem, ".test") || strings.HasSuffix(stem, ".spec")
+	case ".java", ".kt", ".scala", ".cs":
+		// FooTest.java, not Latest.java
+		name = strings.TrimSuffix(name, path.Ext(name))
+		return strings.HasSuffix(name, "Test") || strings.HasSuffix(name, "Tests")
+	case ".rb":
+		return strings.HasSuffix(stem, "_spec") || strings.HasSuffix(stem, "_test")
+	case ".rs":
+		return stem == "tests"
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package ctxtypes
+
+import "testing"
+
+func TestClassifyRole(t *testing.T) {
+	tests := []struct {
+		path string
+		want FileRole
+	}{
+		{"main.go", RoleSource},
+		{"pkg/store/store.go", RoleSource},
+		{"pkg/store/store_test.go", RoleTest},
+		// pytest fixtures, not source despite lacking a test_ prefix
+		{"conftest.py", RoleTest},
+		{"app/tests/conftest.py", RoleTest},
+		{"app/test_views.py", RoleTest},
+		{"app/views_test.py", RoleTest},
+		// a module named after testing isn't a test
+		{"app/testing.py", RoleSource},
+		{"app/contest.py", RoleSource},
+		{"src/app.test.ts", RoleTest},
+		{"src/app.spec.jsx", RoleTest},
+		{"src/latest.ts", RoleSource},
+		{"src/main/java/com/x/UserStoreTest.java", RoleTest},
+		{"src/main/java/com/x/Latest.java", RoleSource},
+		{"lib/user_spec.rb", RoleTest},
+		// fixtures under a test directory are tests whatever their extension
+		{"testdata/config.json", RoleTest},
+		{"src/__tests__/fixtures/users.yaml", RoleTest},
+		{"src/__mocks__/api.ts", RoleTest},
+		{"go.sum", RoleLockfile},
+		{"web/package-lock.json", RoleLockfile},
+		{"Cargo.lock", RoleLockfile},
+		{"Makefile", RoleBuild},
+		{"Dockerfile", RoleBuild},
+		{"Dockerfile.prod", RoleBuild},
+		{"deploy/app.dockerfile", RoleBuild},
+		{"setup.py", RoleBuild},
+		{".github/workflows/ci.yml", RoleBuild},
+		{"go.mod", RoleConfig},
+		{"package.json", RoleConfig},
+		{"config/settings.yaml", RoleConfig},
+		{"requirements-dev.txt", RoleConfig},
+		{"vite.config.ts", RoleConfig},
+		{".eslintrc.js", RoleConfig},
+		{".gitignore", RoleConfig},
+		{"README.md", RoleDocs},
+		{"docs/guide.rst", RoleDocs},
+		{"LICENSE", RoleDocs},
+		{"docs/diagram.svg", RoleDocs},
+		// code examples in the docs are still code
+		{"docs/example.go", RoleSource},
+		{"assets/logo.png", ""},
+		{`src\windows\path_test.go`, RoleTest},
+	}
+	for _, tt := range tests {
+		if got := ClassifyRole(tt.path); got != tt.want {
+			t.Errorf("ClassifyRole(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
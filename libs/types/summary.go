@@ -0,0 +1,83 @@
+package ctxtypes
+
+import (
+	"path"
+	"sort"
+)
+
+// SummaryMaxKeywords is the number of keywords kept for a summarized directory
+const SummaryMaxKeywords = 100
+
+// SummarizeDirectories replaces the content of directories holding more than
+// maxEntries entries with a summary: the count of files below it and their most
+// frequent keywords. Root directories are never summarized. It returns the
+// files each summarized directory held, by directory path.
+func SummarizeDirectories(ctx *ApplicationContext, maxEntries int) map[string][]string {
+	summarized := map[string][]string{}
+	if maxEntries <= 0 {
+		return summarized
+	}
+
+	var walk func(prefix string, node *FileSystemNode)
+	walk = func(prefix string, node *FileSystemNode) {
+		for name, child := range node.Children {
+			if child == nil || !child.Directory || child.Skip {
+				continue
+			}
+			p := path.Join(prefix, name)
+
+			if len(child.Children) > maxEntries {
+				summarized[p] = summarize(p, child)
+				continue
+			}
+			walk(p, child)
+		}
+	}
+
+	for key, root := range ctx.FileSystem {
+		walk("", &root)
+		ctx.FileSystem[key] = root
+	}
+
+	return summarized
+}
+
+// summarize turns a directory node into its summary and returns the files it held
+func summarize(dir string, node *FileSystemNode) []string {
+	files := []string{}
+	counts := map[string]int{}
+
+	WalkFiles(ApplicationContext{FileSystem: map[string]FileSystemNode{"": *node}}, func(p string, n *FileSystemNode) {
+		if n.Skip {
+			return
+		}
+		files = append(files, path.Join(dir, p))
+		for _, k := range n.Keywords {
+			counts[k]++
+		}
+	})
+	sort.Strings(files)
+
+	keywords := make([]string, 0, len(counts))
+	for k := range counts {
+		keywords = append(keywords, k)
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if counts[keywords[i]] != counts[keywords[j]] {
+			return counts[keywords[i]] > counts[keywords[j]]
+		}
+		return keywords[i] < keywords[j]
+	})
+	if len(keywords) > SummaryMaxKeywords {
+		keywords = keywords[:SummaryMaxKeywords]
+	}
+
+	*node = FileSystemNode{
+		Directory: true,
+		Summary:   true,
+		FileCount: len(files),
+		Keywords:  keywords,
+	}
+
+	return files
+}
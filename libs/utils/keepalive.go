@@ -0,0 +1,49 @@
+package ctxutils
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// KeepAliveWait is how long a keepalive connection waits for a message, a ping
+// or a pong before its peer is deemed gone, tolerating a lost ping
+func KeepAliveWait(interval time.Duration) time.Duration {
+	return 2 * interval
+}
+
+// KeepAlive pings the connection every interval until it is closed, so that
+// intermediaries don't drop it while idle. Each ping and pong received extends
+// the read deadline by KeepAliveWait, callers set it before each read.
+func KeepAlive(c *websocket.Conn, interval time.Duration) {
+	wait := KeepAliveWait(interval)
+
+	c.SetPongHandler(func(string) error {
+		return c.SetReadDeadline(time.Now().Add(wait))
+	})
+	c.SetPingHandler(func(data string) error {
+		if err := c.SetReadDeadline(time.Now().Add(wait)); err != nil {
+			return err
+		}
+		err := c.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+		// like the default handler, a pong that can't be written isn't a read error
+		var netErr net.Error
+		if errors.Is(err, websocket.ErrCloseSent) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return nil
+		}
+		return err
+	})
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			// fails once the connection is closed
+			if err := c.WriteControl(websocket.PingMessage, nil, time.Now().Add(interval)); err != nil {
+				return
+			}
+		}
+	}()
+}